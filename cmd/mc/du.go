@@ -0,0 +1,72 @@
+package mc
+
+import (
+	"path"
+	"sort"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/mworzala/mc/internal/pkg/game/storage"
+	"github.com/spf13/cobra"
+)
+
+type duOpts struct {
+	app *cli.App
+}
+
+func newDuCmd(app *cli.App) *cobra.Command {
+	var o duOpts
+
+	cmd := &cobra.Command{
+		Use:   "du",
+		Short: "Show disk usage of profiles and shared game files",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			o.app = app
+			return o.diskUsage()
+		},
+	}
+
+	return cmd
+}
+
+func (o *duOpts) diskUsage() error {
+	var result appModel.DiskUsage
+
+	profileManager := o.app.ProfileManager()
+	names := profileManager.Profiles()
+	sort.Strings(names)
+	for _, name := range names {
+		p, _ := profileManager.GetProfile(name) // Ignore error since we just got the list of names
+		size, err := storage.DirSize(p.Directory)
+		if err != nil {
+			return err
+		}
+		result.Profiles = append(result.Profiles, &appModel.DiskUsageEntry{Name: p.Name, Path: p.Directory, Size: size})
+		result.Total += size
+	}
+
+	for _, name := range []string{"versions", "libraries", "assets"} {
+		dir := path.Join(o.app.ConfigDir, name)
+		size, err := storage.DirSize(dir)
+		if err != nil {
+			return err
+		}
+		result.Shared = append(result.Shared, &appModel.DiskUsageEntry{Name: name, Path: dir, Size: size})
+		result.Total += size
+	}
+
+	refs, err := storage.FindReferences(o.app.ConfigDir, profileVersions(o.app))
+	if err != nil {
+		return err
+	}
+	unreferenced, err := storage.FindUnreferenced(o.app.ConfigDir, refs)
+	if err != nil {
+		return err
+	}
+	for _, entry := range unreferenced {
+		result.Reclaimable += entry.Size
+	}
+
+	return o.app.Present(&result)
+}
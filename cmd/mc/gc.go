@@ -0,0 +1,79 @@
+package mc
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/mworzala/mc/internal/pkg/game/storage"
+	"github.com/spf13/cobra"
+)
+
+type gcOpts struct {
+	app *cli.App
+
+	dryRun bool
+}
+
+func newGcCmd(app *cli.App) *cobra.Command {
+	var o gcOpts
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove versions, libraries and assets no longer used by any profile",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			o.app = app
+			return o.collect()
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only list the files which would be removed")
+
+	return cmd
+}
+
+func (o *gcOpts) collect() error {
+	refs, err := storage.FindReferences(o.app.ConfigDir, profileVersions(o.app))
+	if err != nil {
+		return err
+	}
+
+	unreferenced, err := storage.FindUnreferenced(o.app.ConfigDir, refs)
+	if err != nil {
+		return err
+	}
+
+	if !o.dryRun {
+		if err := storage.Remove(o.app.ConfigDir, unreferenced); err != nil {
+			return fmt.Errorf("garbage collection failed: %w", err)
+		}
+	}
+
+	result := appModel.GarbageCollection{DryRun: o.dryRun}
+	for _, entry := range unreferenced {
+		result.Removed = append(result.Removed, &appModel.StorageEntry{
+			Kind: string(entry.Kind),
+			Path: entry.Path,
+			Size: entry.Size,
+		})
+		result.Total += entry.Size
+	}
+	return o.app.Present(&result)
+}
+
+// profileVersions returns the version of every profile, sorted by profile name.
+func profileVersions(app *cli.App) []string {
+	profileManager := app.ProfileManager()
+
+	names := profileManager.Profiles()
+	sort.Strings(names)
+
+	var result []string
+	for _, name := range names {
+		p, _ := profileManager.GetProfile(name) // Ignore error since we just got the list of names
+		result = append(result, p.Version)
+	}
+	return result
+}
@@ -1,10 +1,16 @@
 package mc
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
 
 	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
 	"github.com/mworzala/mc/internal/pkg/game"
 	"github.com/mworzala/mc/internal/pkg/game/install"
 	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
@@ -19,8 +25,20 @@ type installOpts struct {
 
 	version *gameModel.VersionInfo
 
-	fabric       bool
-	fabricLoader string
+	fabric bool
+	quilt  bool
+	loader string
+
+	forge        bool
+	forgeVersion string
+
+	neoForge        bool
+	neoForgeVersion string
+
+	fromJson string
+	force    bool
+	mrpack   string
+	dryRun   bool
 }
 
 func newInstallCmd(app *cli.App) *cobra.Command {
@@ -29,31 +47,64 @@ func newInstallCmd(app *cli.App) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "install",
 		Short: "Install a new Minecraft version",
-		Args: func(cmd *cobra.Command, args []string) error {
-			o.app = app
-			return o.validateArgs(cmd, args)
-		},
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			o.app = app
-			return o.installSelected(args)
+
+			// Stop manifest updates and downloads on interrupt, partial files are removed by the installer
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+			err := o.validateArgs(ctx, cmd, args)
+			if err == nil {
+				err = o.installSelected(ctx, args)
+			}
+			if ctx.Err() != nil {
+				// Reported by main as interrupted
+				cmd.SilenceErrors, cmd.SilenceUsage = true, true
+				return ctx.Err()
+			}
+			return err
 		},
 	}
 
 	cmd.Flags().BoolVar(&o.fabric, "fabric", false, "Install fabric mod loader")
-	cmd.Flags().StringVar(&o.fabricLoader, "loader", "", "Fabric loader version, ignored without --fabric")
+	cmd.Flags().BoolVar(&o.quilt, "quilt", false, "Install quilt mod loader")
+	cmd.Flags().StringVar(&o.loader, "loader", "", "Fabric or quilt loader version, ignored without --fabric or --quilt")
+	cmd.Flags().BoolVar(&o.forge, "forge", false, "Install forge mod loader")
+	cmd.Flags().StringVar(&o.forgeVersion, "forge-version", "", "Forge version, ignored without --forge")
+	cmd.Flags().BoolVar(&o.neoForge, "neoforge", false, "Install neoforge mod loader")
+	cmd.Flags().StringVar(&o.neoForgeVersion, "neoforge-version", "", "NeoForge version, ignored without --neoforge")
+	cmd.Flags().StringVar(&o.fromJson, "from-json", "", "Install a custom version spec from a file or url, the version argument is omitted")
+	cmd.Flags().BoolVar(&o.force, "force", false, "Replace an installed custom version with the same id, ignored without --from-json")
+	cmd.Flags().StringVar(&o.mrpack, "mrpack", "", "Install a Modrinth modpack from a .mrpack file or slug[@version], the version argument is omitted")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Only list the files which would be downloaded")
+	cmd.MarkFlagsMutuallyExclusive("fabric", "quilt", "forge", "neoforge", "from-json", "mrpack")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", "from-json", "mrpack")
 
 	return cmd
 }
 
-func (o *installOpts) validateArgs(cmd *cobra.Command, args []string) (err error) {
+func (o *installOpts) validateArgs(ctx context.Context, cmd *cobra.Command, args []string) (err error) {
+	// A custom spec or modpack is only validated during installation, the only arg is the name
+	if o.fromJson != "" || o.mrpack != "" {
+		if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+			return err
+		}
+		if len(args) > 0 && !profile.IsValidName(args[0]) {
+			return profile.ErrInvalidName
+		}
+		return nil
+	}
+
 	if err := cobra.RangeArgs(1, 2)(cmd, args); err != nil {
 		return err
 	}
 
 	// Validate version arg (0)
 	versionManager := o.app.VersionManager()
-	if o.version, err = versionManager.FindVanilla(args[0]); errors.Is(err, game.ErrUnknownVersion) {
+	if o.version, err = versionManager.FindVanilla(ctx, args[0]); errors.Is(err, game.ErrUnknownVersion) {
 		return fmt.Errorf("%w: %s", err, args[0])
+	} else if err != nil {
+		return err
 	}
 
 	// Validate name arg (1, optional)
@@ -64,30 +115,110 @@ func (o *installOpts) validateArgs(cmd *cobra.Command, args []string) (err error
 	// Validate flag fabric (and loader)
 	// If fabric is present ensure the selected version is supported, if loader is specified use that
 	if o.fabric {
-		if o.fabricLoader == "" {
-			o.fabricLoader = versionManager.DefaultFabricLoader()
+		if o.loader == "" {
+			o.loader = versionManager.DefaultFabricLoader()
 		}
 
-		o.version, err = versionManager.FindFabric(args[0], o.fabricLoader)
+		o.version, err = versionManager.FindFabric(ctx, args[0], o.loader)
 		if errors.Is(err, game.ErrUnknownFabricVersion) {
 			return fmt.Errorf("%w: %s", err, args[0])
 		}
 		if errors.Is(err, game.ErrUnknownFabricLoader) {
-			return fmt.Errorf("%w: %s", err, o.fabricLoader)
+			return fmt.Errorf("%w: %s", err, o.loader)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate flag quilt (and loader), same as fabric
+	if o.quilt {
+		if o.loader == "" {
+			o.loader = versionManager.DefaultQuiltLoader()
+		}
+
+		o.version, err = versionManager.FindQuilt(ctx, args[0], o.loader)
+		if errors.Is(err, game.ErrUnknownQuiltVersion) {
+			return fmt.Errorf("%w: %s", err, args[0])
+		}
+		if errors.Is(err, game.ErrUnknownQuiltLoader) {
+			return fmt.Errorf("%w: %s", err, o.loader)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate flag forge (and forge version)
+	if o.forge {
+		if o.forgeVersion == "" {
+			o.forgeVersion = versionManager.DefaultForgeLoader(args[0])
+			if o.forgeVersion == "" {
+				return fmt.Errorf("%w: %s (no recommended version, use --forge-version)", game.ErrUnknownForgeVersion, args[0])
+			}
+		}
+
+		o.version, err = versionManager.FindForge(ctx, args[0], o.forgeVersion)
+		if errors.Is(err, game.ErrUnknownForgeVersion) {
+			return fmt.Errorf("%w: %s", err, args[0])
+		}
+		if errors.Is(err, game.ErrUnknownForgeLoader) {
+			return fmt.Errorf("%w: %s", err, o.forgeVersion)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate flag neoforge (and neoforge version)
+	if o.neoForge {
+		if o.neoForgeVersion == "" {
+			o.neoForgeVersion = versionManager.DefaultNeoForgeLoader(args[0])
+			if o.neoForgeVersion == "" {
+				return fmt.Errorf("%w: %s", game.ErrUnknownNeoForgeVersion, args[0])
+			}
+		}
+
+		o.version, err = versionManager.FindNeoForge(ctx, args[0], o.neoForgeVersion)
+		if errors.Is(err, game.ErrUnknownNeoForgeVersion) {
+			return fmt.Errorf("%w: %s", err, args[0])
+		}
+		if errors.Is(err, game.ErrUnknownNeoForgeLoader) {
+			return fmt.Errorf("%w: %s", err, o.neoForgeVersion)
+		}
+		if err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func (o *installOpts) installSelected(args []string) error {
+func (o *installOpts) installSelected(ctx context.Context, args []string) error {
 	// Validation function has done arg validation and option population
 
 	// Install the selected version
-	versionManager := o.app.VersionManager()
-	installer := install.NewInstaller(o.app.ConfigDir, versionManager.FindVanilla)
-	if err := installer.Install(o.version); err != nil {
-		return fmt.Errorf("installation failed: %w", err)
+	if o.mrpack != "" {
+		return o.installMrpack(ctx, args)
+	}
+	if o.dryRun {
+		return o.presentPlan(ctx, o.app.Installer(), args)
+	}
+	if o.fromJson != "" {
+		id, err := o.app.Installer().InstallFromJson(ctx, o.fromJson, o.force)
+		if err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+		o.version = &gameModel.VersionInfo{Id: id}
+
+		// Custom specs have no version arg, so the name is the only arg
+		args = append([]string{id}, args...)
+	} else {
+		id, err := o.app.InstallVersion(ctx, o.version, o.profileType())
+		if err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+		o.version = &gameModel.VersionInfo{Id: id}
 	}
 
 	// Initialize profile
@@ -96,6 +227,12 @@ func (o *installOpts) installSelected(args []string) error {
 	profileName := args[0]
 	if o.fabric {
 		profileName = fmt.Sprintf("%s-fabric", profileName)
+	} else if o.quilt {
+		profileName = fmt.Sprintf("%s-quilt", profileName)
+	} else if o.forge {
+		profileName = fmt.Sprintf("%s-forge", profileName)
+	} else if o.neoForge {
+		profileName = fmt.Sprintf("%s-neoforge", profileName)
 	}
 	if len(args) > 1 {
 		profileName = args[1]
@@ -105,9 +242,14 @@ func (o *installOpts) installSelected(args []string) error {
 		return err
 	}
 
-	p.Type = profile.Vanilla
-	if o.fabric {
-		p.Type = profile.Fabric
+	p.Type = o.profileType()
+	switch p.Type {
+	case profile.Fabric, profile.Quilt:
+		p.Loader = o.loader
+	case profile.Forge:
+		p.Loader = o.forgeVersion
+	case profile.NeoForge:
+		p.Loader = o.neoForgeVersion
 	}
 	p.Version = o.version.Id
 
@@ -118,3 +260,57 @@ func (o *installOpts) installSelected(args []string) error {
 	println("installed", o.version.Id)
 	return nil
 }
+
+// profileType returns the type of profile created for the selected flags.
+func (o *installOpts) profileType() profile.Type {
+	switch {
+	case o.fabric:
+		return profile.Fabric
+	case o.quilt:
+		return profile.Quilt
+	case o.forge:
+		return profile.Forge
+	case o.neoForge:
+		return profile.NeoForge
+	case o.fromJson != "":
+		return profile.Custom
+	}
+	return profile.Vanilla
+}
+
+func (o *installOpts) presentPlan(ctx context.Context, installer *install.Installer, args []string) error {
+	result := appModel.InstallPlan{Version: o.version.Id}
+
+	// Forge style versions are created by their installer, so only the installer and parent version can be planned
+	planned := o.version
+	if o.forge || o.neoForge {
+		result.Files = append(result.Files, &appModel.InstallPlanFile{Kind: "installer", Path: path.Base(o.version.Url), Url: o.version.Url})
+
+		var err error
+		if planned, err = o.app.VersionManager().FindVanilla(ctx, args[0]); err != nil {
+			return err
+		}
+	}
+
+	plan, err := installer.Plan(ctx, planned)
+	if err != nil {
+		return err
+	}
+	for _, file := range plan.Files {
+		rel, err := filepath.Rel(o.app.ConfigDir, file.Path)
+		if err != nil {
+			rel = file.Path
+		}
+		result.Files = append(result.Files, &appModel.InstallPlanFile{
+			Kind:    string(file.Kind),
+			Path:    filepath.ToSlash(rel),
+			Url:     file.Url,
+			Size:    file.Size,
+			Present: file.Present,
+			Cached:  file.Cached,
+		})
+	}
+	result.DownloadSize = plan.DownloadSize
+
+	return o.app.Present(&result)
+}
@@ -0,0 +1,128 @@
+package mc
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/modrinth"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+// installMrpack installs the Minecraft and loader versions required by a Modrinth modpack, and creates a
+// profile containing the modpack files.
+func (o *installOpts) installMrpack(ctx context.Context, args []string) error {
+	archive, cleanup, err := o.openMrpack(ctx)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	index, err := modrinth.ReadPack(&archive.Reader)
+	if err != nil {
+		return err
+	}
+
+	// Check the name before installing anything
+	profileName := profile.ToValidName(index.Name)
+	if len(args) > 0 {
+		profileName = args[0]
+	}
+	profileManager := o.app.ProfileManager()
+	if _, err := profileManager.GetProfile(profileName); err == nil {
+		return fmt.Errorf("%w: %s", profile.ErrNameInUse, profileName)
+	}
+
+	typ, loader := mrpackProfileType(index.Dependencies)
+	gameVersion := index.Dependencies[modrinth.DependencyMinecraft]
+	v, err := o.app.FindVersion(ctx, typ, gameVersion, loader)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, gameVersion)
+	}
+	id, err := o.app.InstallVersion(ctx, v, typ)
+	if err != nil {
+		return fmt.Errorf("installation failed: %w", err)
+	}
+
+	p, err := profileManager.CreateProfile(profileName)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, profileName)
+	}
+	if err := modrinth.InstallPack(ctx, o.app.HttpClient(), &archive.Reader, index, p.Directory); err != nil {
+		// Do not leave a partially installed profile behind
+		_ = profileManager.DeleteProfile(p.Name, true)
+		return fmt.Errorf("modpack installation failed: %w", err)
+	}
+
+	p.Type = typ
+	p.Version = id
+	p.Loader = loader
+	if err := profileManager.Save(); err != nil {
+		return err
+	}
+
+	println("installed", index.Name, index.VersionID)
+	return nil
+}
+
+// openMrpack opens the selected modpack, downloading it from Modrinth if it is not a local file.
+// The returned cleanup function must be called once the archive is no longer needed.
+func (o *installOpts) openMrpack(ctx context.Context) (*zip.ReadCloser, func(), error) {
+	if info, err := os.Stat(o.mrpack); err == nil && !info.IsDir() {
+		archive, err := zip.OpenReader(o.mrpack)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open %s: %w", o.mrpack, err)
+		}
+		return archive, func() { _ = archive.Close() }, nil
+	}
+
+	slug, version, _ := strings.Cut(o.mrpack, "@")
+	client := modrinth.NewClient(o.app.Build.Version, o.app.HttpClient())
+	v, err := client.FindProjectVersion(ctx, slug, version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find modpack %s: %w", o.mrpack, err)
+	}
+	file := v.PrimaryFile()
+	if file == nil {
+		return nil, nil, fmt.Errorf("%w: %s has no files", modrinth.ErrInvalidPack, o.mrpack)
+	}
+
+	tempDir, err := os.MkdirTemp("", "mc-mrpack-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	packFile := path.Join(tempDir, path.Base(file.Filename))
+	dl := util.FileDownload{Url: file.Url, Sha1: file.Hashes["sha1"], Size: file.Size}
+	if err := util.Download(ctx, o.app.HttpClient(), packFile, dl); err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, nil, fmt.Errorf("failed to download modpack: %w", err)
+	}
+	archive, err := zip.OpenReader(packFile)
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return nil, nil, fmt.Errorf("failed to open %s: %w", file.Filename, err)
+	}
+	return archive, func() {
+		_ = archive.Close()
+		_ = os.RemoveAll(tempDir)
+	}, nil
+}
+
+// mrpackProfileType returns the profile type and loader version for the dependencies of a modpack.
+func mrpackProfileType(dependencies map[string]string) (profile.Type, string) {
+	switch {
+	case dependencies[modrinth.DependencyFabric] != "":
+		return profile.Fabric, dependencies[modrinth.DependencyFabric]
+	case dependencies[modrinth.DependencyQuilt] != "":
+		return profile.Quilt, dependencies[modrinth.DependencyQuilt]
+	case dependencies[modrinth.DependencyForge] != "":
+		return profile.Forge, dependencies[modrinth.DependencyForge]
+	case dependencies[modrinth.DependencyNeoForge] != "":
+		return profile.NeoForge, dependencies[modrinth.DependencyNeoForge]
+	}
+	return profile.Vanilla, ""
+}
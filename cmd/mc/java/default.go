@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/mworzala/mc/internal/pkg/cli"
-	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
 	"github.com/spf13/cobra"
 )
 
@@ -57,11 +56,7 @@ func (o *defaultJavaOpts) getDefault() error {
 		install = javaManager.GetInstallation(javaManager.GetDefault())
 	}
 
-	return o.app.Present(&appModel.JavaInstallation{
-		Name:    install.Name,
-		Path:    install.Path,
-		Version: install.Version,
-	})
+	return o.app.Present(installationModel(install))
 }
 
 func (o *defaultJavaOpts) setDefault(args []string) error {
@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/mworzala/mc/internal/pkg/cli"
-	"github.com/mworzala/mc/internal/pkg/cli/model"
 	"github.com/spf13/cobra"
 )
 
@@ -50,9 +49,5 @@ func (o *discoverJavaOpts) discover(args []string) error {
 		return err
 	}
 
-	return o.app.Present(&model.JavaInstallation{
-		Name:    install.Name,
-		Path:    install.Path,
-		Version: install.Version,
-	})
+	return o.app.Present(installationModel(install))
 }
@@ -0,0 +1,59 @@
+package java
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+type installJavaOpts struct {
+	app *cli.App
+
+	setDefault bool
+}
+
+func newInstallCmd(app *cli.App) *cobra.Command {
+	var o installJavaOpts
+
+	cmd := &cobra.Command{
+		Use:   "install [component|major|vendor@major]",
+		Short: "Install a Mojang java runtime (eg java-runtime-delta or 21) or a vendor JDK (eg temurin@21)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.app = app
+			return o.install(cmd.Context(), args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.setDefault, "set-default", false, "Set the new installation as the default")
+
+	return cmd
+}
+
+func (o *installJavaOpts) install(ctx context.Context, args []string) error {
+	name := ""
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	javaManager := o.app.JavaManager()
+	install, err := javaManager.InstallRuntime(ctx, name)
+	if err != nil {
+		return fmt.Errorf("java install failed: %w", err)
+	}
+
+	// Update default if there is not one, or the flag was set
+	if o.setDefault || javaManager.GetDefault() == "" {
+		if err := javaManager.SetDefault(install.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := javaManager.Save(); err != nil {
+		return err
+	}
+
+	return o.app.Present(installationModel(install))
+}
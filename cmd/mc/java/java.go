@@ -2,6 +2,8 @@ package java
 
 import (
 	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/mworzala/mc/internal/pkg/java"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +16,23 @@ func NewJavaCmd(app *cli.App) *cobra.Command {
 	cmd.AddCommand(newListCmd(app))
 	cmd.AddCommand(newDefaultCmd(app))
 	cmd.AddCommand(newDiscoverCommand(app))
+	cmd.AddCommand(newInstallCmd(app))
+	cmd.AddCommand(newScanCmd(app))
+	cmd.AddCommand(newRemoveCmd(app))
+	cmd.AddCommand(newRenameCmd(app))
+	cmd.AddCommand(newRefreshCmd(app))
 
 	return cmd
 }
+
+func installationModel(install *java.Installation) *appModel.JavaInstallation {
+	return &appModel.JavaInstallation{
+		Name:        install.Name,
+		Path:        install.Path,
+		Arch:        install.Arch,
+		Version:     install.Version,
+		Vendor:      install.Vendor,
+		FullVersion: install.FullVersion,
+		Broken:      install.Broken,
+	}
+}
@@ -1,6 +1,8 @@
 package java
 
 import (
+	"slices"
+
 	"github.com/mworzala/mc/internal/pkg/cli"
 	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
 	"github.com/spf13/cobra"
@@ -29,14 +31,13 @@ func newListCmd(app *cli.App) *cobra.Command {
 func (o *listJavaOpts) listInstallations() error {
 	javaManager := o.app.JavaManager()
 
+	names := javaManager.Installations()
+	slices.Sort(names)
+
 	var result appModel.JavaInstallationList
-	for _, name := range javaManager.Installations() {
+	for _, name := range names {
 		install := javaManager.GetInstallation(name)
-		result = append(result, &appModel.JavaInstallation{
-			Name:    install.Name,
-			Path:    install.Path,
-			Version: install.Version,
-		})
+		result = append(result, installationModel(install))
 	}
 
 	return o.app.Present(result)
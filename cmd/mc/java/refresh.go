@@ -0,0 +1,46 @@
+package java
+
+import (
+	"slices"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/spf13/cobra"
+)
+
+type refreshJavaOpts struct {
+	app *cli.App
+}
+
+func newRefreshCmd(app *cli.App) *cobra.Command {
+	var o refreshJavaOpts
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Check every Java installation again, updating versions and marking broken installations",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			o.app = app
+			return o.refresh()
+		},
+	}
+
+	return cmd
+}
+
+func (o *refreshJavaOpts) refresh() error {
+	javaManager := o.app.JavaManager()
+	javaManager.Refresh()
+	if err := javaManager.Save(); err != nil {
+		return err
+	}
+
+	names := javaManager.Installations()
+	slices.Sort(names)
+
+	var result appModel.JavaInstallationList
+	for _, name := range names {
+		result = append(result, installationModel(javaManager.GetInstallation(name)))
+	}
+	return o.app.Present(result)
+}
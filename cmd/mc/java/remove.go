@@ -0,0 +1,37 @@
+package java
+
+import (
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+type removeJavaOpts struct {
+	app *cli.App
+}
+
+func newRemoveCmd(app *cli.App) *cobra.Command {
+	var o removeJavaOpts
+
+	cmd := &cobra.Command{
+		Use:     "remove",
+		Aliases: []string{"rm"},
+		Short:   "Remove a Java installation, deleting it if it was installed by mc",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.remove(args)
+		},
+	}
+
+	return cmd
+}
+
+func (o *removeJavaOpts) remove(args []string) error {
+	javaManager := o.app.JavaManager()
+	if err := javaManager.Remove(args[0]); err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+	return javaManager.Save()
+}
@@ -0,0 +1,49 @@
+package java
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/mworzala/mc/internal/pkg/java"
+	"github.com/spf13/cobra"
+)
+
+type renameJavaOpts struct {
+	app *cli.App
+}
+
+func newRenameCmd(app *cli.App) *cobra.Command {
+	var o renameJavaOpts
+
+	cmd := &cobra.Command{
+		Use:     "rename",
+		Aliases: []string{"mv"},
+		Short:   "Rename a Java installation",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.rename(args)
+		},
+	}
+
+	return cmd
+}
+
+func (o *renameJavaOpts) rename(args []string) error {
+	if args[1] == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	javaManager := o.app.JavaManager()
+	if err := javaManager.Rename(args[0], args[1]); errors.Is(err, java.ErrInstallationExists) {
+		return fmt.Errorf("%w: %s", err, args[1])
+	} else if err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+	if err := javaManager.Save(); err != nil {
+		return err
+	}
+
+	return o.app.Present(installationModel(javaManager.GetInstallation(args[1])))
+}
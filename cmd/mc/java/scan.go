@@ -0,0 +1,49 @@
+package java
+
+import (
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/spf13/cobra"
+)
+
+type scanJavaOpts struct {
+	app *cli.App
+}
+
+func newScanCmd(app *cli.App) *cobra.Command {
+	var o scanJavaOpts
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Search the known locations for new Java installations",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			o.app = app
+			return o.scan()
+		},
+	}
+
+	return cmd
+}
+
+func (o *scanJavaOpts) scan() error {
+	javaManager := o.app.JavaManager()
+	added := javaManager.Scan()
+
+	// Update default if there is not one
+	if len(added) > 0 && javaManager.GetDefault() == "" {
+		if err := javaManager.SetDefault(added[0].Name); err != nil {
+			return err
+		}
+	}
+
+	if err := javaManager.Save(); err != nil {
+		return err
+	}
+
+	var result appModel.JavaInstallationList
+	for _, install := range added {
+		result = append(result, installationModel(install))
+	}
+	return o.app.Present(result)
+}
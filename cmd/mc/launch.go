@@ -1,14 +1,17 @@
 package mc
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/mworzala/mc/internal/pkg/java"
 
 	"github.com/mworzala/mc/internal/pkg/cli"
 
 	"github.com/mworzala/mc/internal/pkg/game/launch"
+	"github.com/mworzala/mc/internal/pkg/game/rule"
 	"github.com/spf13/cobra"
 )
 
@@ -31,9 +34,9 @@ func newLaunchCmd(app *cli.App) *cobra.Command {
 		Short:   "Launch a profile (Minecraft installation)",
 		Aliases: []string{"run"},
 		Args:    cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			o.app = app
-			return o.launch(args)
+			return o.launch(cmd.Context(), args)
 		},
 	}
 
@@ -47,7 +50,7 @@ func newLaunchCmd(app *cli.App) *cobra.Command {
 	return cmd
 }
 
-func (o *launchOpts) launch(args []string) error {
+func (o *launchOpts) launch(ctx context.Context, args []string) error {
 
 	profileManager := o.app.ProfileManager()
 	p, err := profileManager.GetProfile(args[0])
@@ -65,6 +68,16 @@ func (o *launchOpts) launch(args []string) error {
 		return err
 	}
 
+	spec, err := launch.ReadSpec(o.app.ConfigDir, p.Version)
+	if err != nil {
+		return err
+	}
+	requiredMajor := 0
+	if spec.JavaVersion != nil {
+		requiredMajor = spec.JavaVersion.MajorVersion
+	}
+	hostArch := rule.HostArch()
+
 	javaManager := o.app.JavaManager()
 	var javaInstall *java.Installation
 	if p.Config().Java != "" {
@@ -76,11 +89,34 @@ func (o *launchOpts) launch(args []string) error {
 	// If still unset (or was invalid), use the default
 	if javaInstall == nil {
 		javaInstall = javaManager.GetInstallation(javaManager.GetDefault())
+
+		// Use the runtime requested by the version if the default is missing or incompatible
+		if required := spec.JavaVersion; required != nil {
+			if javaInstall != nil {
+				_, err = java.CheckCompatibility(javaInstall, hostArch, required.MajorVersion)
+			}
+			if javaInstall == nil || err != nil {
+				if javaInstall, err = o.requiredRuntime(ctx, required.Component, required.MajorVersion); err != nil {
+					return err
+				}
+			}
+		}
 		if javaInstall == nil {
 			return fmt.Errorf("no default java installation is set")
 		}
 	}
 
+	warning, err := java.CheckCompatibility(javaInstall, hostArch, requiredMajor)
+	if err != nil {
+		if alternative := java.FindCompatible(javaManager, hostArch, requiredMajor); alternative != nil {
+			return fmt.Errorf("%w\n%s is compatible, use it with `mc java default %s`", err, alternative.Name, alternative.Name)
+		}
+		return err
+	}
+	if warning != "" {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
 	var quickPlay *launch.QuickPlay
 	if o.quickPlaySingleplayer != "" {
 		quickPlay = &launch.QuickPlay{
@@ -99,5 +135,29 @@ func (o *launchOpts) launch(args []string) error {
 		}
 	}
 
-	return launch.LaunchProfile(o.app.ConfigDir, p, acc, accessToken, javaInstall, o.tail, quickPlay)
+	return launch.LaunchProfile(o.app.ConfigDir, p, acc, accessToken, javaInstall, o.tail, quickPlay, o.app.GameManager())
+}
+
+// requiredRuntime returns the installation of the given Mojang runtime component, installing it if missing.
+// Without a component, any runtime installed by mc with the major version is used.
+func (o *launchOpts) requiredRuntime(ctx context.Context, component string, majorVersion int) (*java.Installation, error) {
+	javaManager := o.app.JavaManager()
+	if component == "" {
+		if install := javaManager.FindRuntime(majorVersion); install != nil {
+			return install, nil
+		}
+		component = strconv.Itoa(majorVersion)
+	} else if install := javaManager.GetInstallation(component); install != nil {
+		return install, nil
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "installing java runtime %s\n", component)
+	install, err := javaManager.InstallRuntime(ctx, component)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install java runtime %s: %w", component, err)
+	}
+	if err := javaManager.Save(); err != nil {
+		return nil, err
+	}
+	return install, nil
 }
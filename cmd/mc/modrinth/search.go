@@ -65,7 +65,7 @@ func (o *searchOpts) validateArgs(cmd *cobra.Command, args []string) (err error)
 func (o *searchOpts) execute(args []string) error {
 	// Validation function has done arg validation and option population
 
-	client := modrinth.NewClient(o.app.Build.Version)
+	client := modrinth.NewClient(o.app.Build.Version, o.app.HttpClient())
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
@@ -0,0 +1,50 @@
+package profile
+
+import (
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+type cloneProfileOpts struct {
+	app *cli.App
+
+	noFiles bool
+}
+
+func newCloneCmd(app *cli.App) *cobra.Command {
+	var o cloneProfileOpts
+
+	cmd := &cobra.Command{
+		Use:     "clone",
+		Aliases: []string{"copy", "cp"},
+		Short:   "Create a copy of a profile",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.cloneProfile(args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.noFiles, "no-files", false, "Only copy the profile config, not the game directory contents")
+
+	return cmd
+}
+
+func (o *cloneProfileOpts) cloneProfile(args []string) error {
+	profileManager := o.app.ProfileManager()
+	if _, err := profileManager.GetProfile(args[0]); err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+
+	p, err := profileManager.CloneProfile(args[0], args[1], !o.noFiles)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[1])
+	}
+	if err := profileManager.Save(); err != nil {
+		return err
+	}
+
+	return o.app.Present(profileModel(p))
+}
@@ -0,0 +1,50 @@
+package profile
+
+import (
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+type deleteProfileOpts struct {
+	app *cli.App
+
+	keepFiles bool
+}
+
+func newDeleteCmd(app *cli.App) *cobra.Command {
+	var o deleteProfileOpts
+
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Aliases: []string{"remove", "rm"},
+		Short:   "Delete a profile",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.deleteProfile(args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.keepFiles, "keep-files", false, "Keep the game directory of the profile")
+
+	return cmd
+}
+
+func (o *deleteProfileOpts) deleteProfile(args []string) error {
+	profileManager := o.app.ProfileManager()
+	p, err := profileManager.GetProfile(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+
+	if o.app.GameManager().IsRunning(p.Name) {
+		return fmt.Errorf("profile %s is running, close the game first", p.Name)
+	}
+
+	if err := profileManager.DeleteProfile(p.Name, !o.keepFiles); err != nil {
+		return err
+	}
+	return profileManager.Save()
+}
@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/spf13/cobra"
+)
+
+type exportProfileOpts struct {
+	app *cli.App
+
+	include []string
+	file    string
+}
+
+func newExportCmd(app *cli.App) *cobra.Command {
+	var o exportProfileOpts
+
+	cmd := &cobra.Command{
+		Use:   "export <name>",
+		Short: "Export a profile to an archive",
+		Long: fmt.Sprintf(`Export a profile to a zip archive which can be imported with 'mc profile import'.
+
+The archive contains the profile type, version and config, along with the selected game directory entries
+(default %v). The version, libraries and assets are not included, they are installed again on import.
+The java installation set in the config is not exported, the default or required runtime is used instead.`, profile.DefaultExportEntries),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.exportProfile(args)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&o.include, "include", profile.DefaultExportEntries, "Game directory entries to include, eg saves,mods")
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Archive to write (default <name>.zip)")
+
+	return cmd
+}
+
+func (o *exportProfileOpts) exportProfile(args []string) error {
+	p, err := o.app.ProfileManager().GetProfile(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+	if p.Type == profile.Unknown {
+		return fmt.Errorf("profile is not installed: %s", p.Name)
+	}
+	for _, entry := range o.include {
+		if !profile.IsArchiveEntry(entry) {
+			return fmt.Errorf("invalid include: %s", entry)
+		}
+	}
+
+	spec, specData, err := readInstalledSpec(o.app.ConfigDir, p.Version)
+	if err != nil {
+		return err
+	}
+
+	metadata := profile.ArchiveMetadata{
+		Name:        p.Name,
+		Type:        p.Type,
+		Version:     p.Version,
+		GameVersion: gameVersion(spec),
+		Loader:      p.Loader,
+	}
+	if p.Type == profile.Custom {
+		metadata.Spec = specData
+	}
+
+	file := o.file
+	if file == "" {
+		file = fmt.Sprintf("%s.zip", p.Name)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := profile.ExportArchive(f, p, &metadata, o.include); err != nil {
+		_ = os.Remove(file)
+		return err
+	}
+
+	println("exported", file)
+	return nil
+}
+
+// readInstalledSpec reads the spec of an installed version, returning both the parsed and raw spec.
+func readInstalledSpec(dataDir, id string) (*gameModel.VersionSpec, []byte, error) {
+	data, err := os.ReadFile(path.Join(dataDir, "versions", id, fmt.Sprintf("%s.json", id)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read version %s: %w", id, err)
+	}
+	var spec gameModel.VersionSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, nil, fmt.Errorf("failed to read version %s: %w", id, err)
+	}
+	return &spec, data, nil
+}
+
+// gameVersion returns the Minecraft version an installed version is based on.
+func gameVersion(spec *gameModel.VersionSpec) string {
+	if spec.InheritsFrom != "" {
+		return spec.InheritsFrom
+	}
+	return spec.Id
+}
@@ -0,0 +1,287 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/mworzala/mc/internal/pkg/modrinth"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// Directories containing files which may be downloaded from Modrinth rather than included in the pack
+var modrinthContentDirs = []string{"mods", "resourcepacks", "shaderpacks"}
+
+type exportMrpackOpts struct {
+	app *cli.App
+
+	file    string
+	version string
+	summary string
+
+	include    []string
+	exclude    []string
+	clientOnly []string
+	serverOnly []string
+	optional   []string
+}
+
+func newExportMrpackCmd(app *cli.App) *cobra.Command {
+	var o exportMrpackOpts
+
+	cmd := &cobra.Command{
+		Use:   "export-mrpack <name>",
+		Short: "Export a profile as a Modrinth modpack",
+		Long: `Export a profile as a Modrinth modpack (.mrpack).
+
+Mods, resource packs and shaders which are published on Modrinth are added as downloads, every other file is
+added as an override. Globs match paths in the game directory, or any of their parent directories, so
+'mods' matches every file in the mods directory and 'mods/*.disabled' only matches disabled mods.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.app = app
+
+			// Stop identifying files on interrupt, the modpack is only written afterwards
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+			err := o.exportMrpack(ctx, args)
+			if ctx.Err() != nil {
+				// Reported by main as interrupted
+				cmd.SilenceErrors, cmd.SilenceUsage = true, true
+				return ctx.Err()
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.file, "file", "f", "", "Modpack to write (default <name>.mrpack)")
+	cmd.Flags().StringVar(&o.version, "version", "1.0.0", "Modpack version")
+	cmd.Flags().StringVar(&o.summary, "summary", "", "Modpack summary")
+	cmd.Flags().StringSliceVar(&o.include, "include", profile.DefaultExportEntries, "Globs of files to include")
+	cmd.Flags().StringSliceVar(&o.exclude, "exclude", nil, "Globs of files to exclude")
+	cmd.Flags().StringSliceVar(&o.clientOnly, "client-only", nil, "Globs of files which are not supported on the server")
+	cmd.Flags().StringSliceVar(&o.serverOnly, "server-only", nil, "Globs of files which are not supported on the client")
+	cmd.Flags().StringSliceVar(&o.optional, "optional", nil, "Globs of files which are optional")
+
+	return cmd
+}
+
+func (o *exportMrpackOpts) exportMrpack(ctx context.Context, args []string) error {
+	p, err := o.app.ProfileManager().GetProfile(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+	for _, patterns := range [][]string{o.include, o.exclude, o.clientOnly, o.serverOnly, o.optional} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%w: %s", err, pattern)
+			}
+		}
+	}
+
+	index := &modrinth.PackIndex{Name: p.Name, VersionID: o.version, Summary: o.summary}
+	if index.Dependencies, err = o.dependencies(p); err != nil {
+		return err
+	}
+
+	// Find the included files, and identify the content files on Modrinth by their hash
+	var files []string
+	var hashes []string
+	fileHashes := make(map[string]map[string]string)
+	fileSizes := make(map[string]int64)
+	err = filepath.WalkDir(p.Directory, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(p.Directory, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			// Directories such as saves are not walked unless something inside them may be included
+			if rel != "." && (!mayMatchInside(o.include, rel) || matchAny(o.exclude, rel)) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || rel == "config.json" || !matchAny(o.include, rel) || matchAny(o.exclude, rel) {
+			return nil
+		}
+		files = append(files, rel)
+
+		if isModrinthContent(rel) {
+			if fileHashes[rel], fileSizes[rel], err = modrinth.HashFile(file); err != nil {
+				return err
+			}
+			hashes = append(hashes, fileHashes[rel]["sha1"])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	versions := make(map[string]*modrinth.Version)
+	if len(hashes) > 0 {
+		client := modrinth.NewClient(o.app.Build.Version, o.app.HttpClient())
+		if versions, err = client.GetVersionsFromHashes(ctx, hashes); errors.Is(err, util.ErrOffline) {
+			// Every file is added as an override instead
+			_, _ = fmt.Fprintln(os.Stderr, "warning: cannot identify files on Modrinth while offline")
+			versions = nil
+		} else if err != nil {
+			return fmt.Errorf("failed to identify files on Modrinth: %w", err)
+		}
+	}
+
+	index.Files = []*modrinth.PackFile{}
+	var overrides []*modrinth.PackOverride
+	for _, rel := range files {
+		env := o.fileEnv(rel)
+		if hash := fileHashes[rel]["sha1"]; hash != "" && versions[hash] != nil {
+			if file := versions[hash].FileWithHash(hash); file != nil {
+				index.Files = append(index.Files, &modrinth.PackFile{
+					Path:      rel,
+					Hashes:    fileHashes[rel],
+					Env:       env,
+					Downloads: []string{file.Url},
+					FileSize:  fileSizes[rel],
+				})
+				continue
+			}
+		}
+		overrides = append(overrides, &modrinth.PackOverride{Path: rel, File: filepath.Join(p.Directory, filepath.FromSlash(rel)), Env: env})
+	}
+
+	file := o.file
+	if file == "" {
+		file = fmt.Sprintf("%s.mrpack", p.Name)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := modrinth.WritePack(f, index, overrides); err != nil {
+		_ = os.Remove(file)
+		return err
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "%d files from Modrinth, %d overrides\n", len(index.Files), len(overrides))
+	println("exported", file)
+	return nil
+}
+
+// dependencies returns the pack dependencies for the version and loader of the profile.
+func (o *exportMrpackOpts) dependencies(p *profile.Profile) (map[string]string, error) {
+	if p.Type == profile.Unknown {
+		return nil, fmt.Errorf("profile is not installed: %s", p.Name)
+	}
+	spec, _, err := readInstalledSpec(o.app.ConfigDir, p.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := map[string]string{modrinth.DependencyMinecraft: gameVersion(spec)}
+	switch p.Type {
+	case profile.Fabric:
+		dependencies[modrinth.DependencyFabric] = p.Loader
+	case profile.Quilt:
+		dependencies[modrinth.DependencyQuilt] = p.Loader
+	case profile.Forge:
+		dependencies[modrinth.DependencyForge] = p.Loader
+	case profile.NeoForge:
+		dependencies[modrinth.DependencyNeoForge] = p.Loader
+	case profile.Custom:
+		return nil, fmt.Errorf("custom versions cannot be exported as a modpack: %s", p.Version)
+	}
+	return dependencies, nil
+}
+
+// fileEnv returns the env of a file from the side flags, or nil if it is required on both sides.
+func (o *exportMrpackOpts) fileEnv(rel string) *modrinth.PackEnv {
+	env := modrinth.PackEnv{Client: modrinth.Required, Server: modrinth.Required}
+	if matchAny(o.clientOnly, rel) {
+		env.Server = modrinth.Unsupported
+	}
+	if matchAny(o.serverOnly, rel) {
+		env.Client = modrinth.Unsupported
+	}
+	if matchAny(o.optional, rel) {
+		if env.Client == modrinth.Required {
+			env.Client = modrinth.Optional
+		}
+		if env.Server == modrinth.Required {
+			env.Server = modrinth.Optional
+		}
+	}
+
+	if env.Client == modrinth.Required && env.Server == modrinth.Required {
+		return nil
+	}
+	return &env
+}
+
+// matchAny returns true if any of the globs matches the slash separated path or one of its parent directories.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, "/")
+		for p := rel; p != "." && p != "/"; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mayMatchInside returns true if any of the globs matches the slash separated directory, one of its parent
+// directories or possibly a path inside it.
+func mayMatchInside(patterns []string, dir string) bool {
+	if matchAny(patterns, dir) {
+		return true
+	}
+	dirParts := strings.Split(dir, "/")
+	for _, pattern := range patterns {
+		parts := strings.Split(strings.TrimSuffix(pattern, "/"), "/")
+		if len(parts) <= len(dirParts) {
+			continue
+		}
+		match := true
+		for i, part := range dirParts {
+			if ok, _ := path.Match(parts[i], part); !ok {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// isModrinthContent returns true if the file may be published on Modrinth, eg a mod jar.
+func isModrinthContent(rel string) bool {
+	dir, name, ok := strings.Cut(rel, "/")
+	if !ok || strings.Contains(name, "/") {
+		return false
+	}
+	ext := path.Ext(name)
+	for _, contentDir := range modrinthContentDirs {
+		if dir == contentDir && (ext == ".jar" || ext == ".zip") {
+			return true
+		}
+	}
+	return false
+}
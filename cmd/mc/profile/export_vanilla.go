@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/mworzala/mc/internal/pkg/launcher"
+	"github.com/mworzala/mc/internal/pkg/platform"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/spf13/cobra"
+)
+
+// Exported installations are keyed by profile name with this prefix, so that exporting again updates them
+const vanillaInstallationPrefix = "mc-"
+
+type exportVanillaOpts struct {
+	app *cli.App
+
+	dir string
+}
+
+func newExportVanillaCmd(app *cli.App) *cobra.Command {
+	var o exportVanillaOpts
+
+	cmd := &cobra.Command{
+		Use:   "export-vanilla [profile...]",
+		Short: "Export profiles as vanilla launcher installations",
+		Long: `Export profiles as vanilla launcher installations (to launcher_profiles.json).
+
+Every installed profile is exported unless some are named. The profile versions are copied to the launcher
+versions directory, and the installations use the profile game directories. Forge versions must be installed
+by their installer for the vanilla launcher to run them.`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.exportVanilla(args)
+		},
+	}
+
+	cmd.Flags().StringVar(&o.dir, "dir", "", "Vanilla launcher directory (default .minecraft)")
+
+	return cmd
+}
+
+func (o *exportVanillaOpts) exportVanilla(args []string) (err error) {
+	if o.dir == "" {
+		if o.dir, err = platform.MinecraftDir(); err != nil {
+			return err
+		}
+	}
+	launcherProfiles, err := launcher.ReadProfiles(o.dir)
+	if err != nil {
+		return err
+	}
+
+	profileManager := o.app.ProfileManager()
+	var profiles []*profile.Profile
+	for _, name := range args {
+		p, err := profileManager.GetProfile(name)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, name)
+		}
+		if p.Type == profile.Unknown {
+			return fmt.Errorf("profile is not installed: %s", p.Name)
+		}
+		profiles = append(profiles, p)
+	}
+	if len(args) == 0 {
+		names := profileManager.Profiles()
+		slices.Sort(names)
+		for _, name := range names {
+			if p, _ := profileManager.GetProfile(name); p.Type != profile.Unknown {
+				profiles = append(profiles, p)
+			}
+		}
+	}
+
+	for _, p := range profiles {
+		if err := launcher.CopyVersion(path.Join(o.app.ConfigDir, "versions"), path.Join(o.dir, "versions"), p.Version); err != nil {
+			return fmt.Errorf("failed to copy version %s: %w", p.Version, err)
+		}
+
+		key := vanillaInstallationPrefix + strings.ToLower(p.Name)
+		install := launcherProfiles.Installations[key]
+		if install == nil {
+			install = &launcher.Installation{Created: time.Now().UTC().Format(time.RFC3339), Icon: "Grass"}
+			launcherProfiles.Installations[key] = install
+		}
+
+		config := p.Config()
+		install.Name = p.Name
+		install.Type = launcher.TypeCustom
+		install.LastVersionId = p.Version
+		install.GameDir = p.Directory
+		install.JavaArgs = launcher.FormatJavaArgs(config.JvmArgs, config.MinMemory, config.MaxMemory)
+		install.JavaDir = ""
+		if config.Java != "" {
+			if javaInstall := o.app.JavaManager().GetInstallation(config.Java); javaInstall != nil {
+				install.JavaDir = javaInstall.Path
+			}
+		}
+	}
+
+	if err := launcherProfiles.Save(); err != nil {
+		return err
+	}
+	for _, p := range profiles {
+		println("exported", p.Name)
+	}
+	return nil
+}
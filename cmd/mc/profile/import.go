@@ -0,0 +1,125 @@
+package profile
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/spf13/cobra"
+)
+
+type importProfileOpts struct {
+	app *cli.App
+
+	force bool
+}
+
+func newImportCmd(app *cli.App) *cobra.Command {
+	var o importProfileOpts
+
+	cmd := &cobra.Command{
+		Use:   "import <archive> [name]",
+		Short: "Import a profile from an archive created by 'mc profile export'",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.app = app
+
+			// Stop downloads on interrupt, the partially imported profile is deleted
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+			err := o.importProfile(ctx, args)
+			if ctx.Err() != nil {
+				cmd.SilenceErrors, cmd.SilenceUsage = true, true
+				return ctx.Err()
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.force, "force", false, "Replace an installed custom version with the same id as the archive version")
+
+	return cmd
+}
+
+func (o *importProfileOpts) importProfile(ctx context.Context, args []string) error {
+	archive, err := zip.OpenReader(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", args[0], err)
+	}
+	defer archive.Close()
+
+	metadata, err := profile.ReadArchiveMetadata(&archive.Reader)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+
+	name := metadata.Name
+	if len(args) > 1 {
+		name = args[1]
+	}
+
+	profileManager := o.app.ProfileManager()
+	p, err := profileManager.CreateProfile(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, name)
+	}
+
+	if err := o.restoreProfile(ctx, &archive.Reader, p, metadata); err != nil {
+		// Do not leave a partially imported profile behind
+		_ = profileManager.DeleteProfile(p.Name, true)
+		return err
+	}
+	if err := profileManager.Save(); err != nil {
+		return err
+	}
+
+	return o.app.Present(profileModel(p))
+}
+
+// restoreProfile installs the version of the archive and restores its files into the (new) profile.
+func (o *importProfileOpts) restoreProfile(ctx context.Context, archive *zip.Reader, p *profile.Profile, metadata *profile.ArchiveMetadata) error {
+	version := metadata.Version
+	if metadata.Type == profile.Custom {
+		if len(metadata.Spec) == 0 {
+			return fmt.Errorf("%w: missing custom version spec", profile.ErrInvalidArchive)
+		}
+
+		// The installer reads custom specs from a file, as they are given to `mc install --from-json`
+		tempDir, err := os.MkdirTemp("", "mc-import-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tempDir)
+		specFile := path.Join(tempDir, "spec.json")
+		if err := os.WriteFile(specFile, metadata.Spec, 0644); err != nil {
+			return err
+		}
+
+		if version, err = o.app.Installer().InstallFromJson(ctx, specFile, o.force); err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+	} else {
+		v, err := o.app.FindVersion(ctx, metadata.Type, metadata.GameVersion, metadata.Loader)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, metadata.Version)
+		}
+		if version, err = o.app.InstallVersion(ctx, v, metadata.Type); err != nil {
+			return fmt.Errorf("installation failed: %w", err)
+		}
+	}
+	println("installed", version)
+
+	if err := profile.ImportArchiveFiles(archive, p, metadata); err != nil {
+		return fmt.Errorf("failed to restore files: %w", err)
+	}
+
+	p.Type = metadata.Type
+	p.Version = version
+	p.Loader = metadata.Loader
+	return nil
+}
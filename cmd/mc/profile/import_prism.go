@@ -0,0 +1,168 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/mworzala/mc/internal/pkg/prism"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+type importPrismOpts struct {
+	app *cli.App
+
+	all  bool
+	copy bool
+}
+
+func newImportPrismCmd(app *cli.App) *cobra.Command {
+	var o importPrismOpts
+
+	cmd := &cobra.Command{
+		Use:     "import-prism <instance dir | --all>",
+		Aliases: []string{"import-multimc"},
+		Short:   "Import MultiMC/Prism Launcher instances as profiles",
+		Long: `Import MultiMC/Prism Launcher instances as profiles.
+
+The version and mod loader of the instance are installed, and the profile uses the instance game directory
+unless --copy is set. Java arguments and memory settings of the instance are copied to the profile config, which
+is kept by mc rather than in the instance directory when it is used in place.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if o.all {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.app = app
+
+			// Stop downloads on interrupt, the partially imported profile is deleted
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+			err := o.importPrism(ctx, args)
+			if ctx.Err() != nil {
+				cmd.SilenceErrors, cmd.SilenceUsage = true, true
+				return ctx.Err()
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.all, "all", false, "Import every instance of the Prism Launcher installation")
+	cmd.Flags().BoolVar(&o.copy, "copy", false, "Copy the instance game directory rather than using it")
+
+	return cmd
+}
+
+func (o *importPrismOpts) importPrism(ctx context.Context, args []string) error {
+	profileManager := o.app.ProfileManager()
+
+	if !o.all {
+		instance, err := prism.ReadInstance(args[0])
+		if err != nil {
+			return err
+		}
+		p, err := o.importInstance(ctx, instance)
+		if err != nil {
+			return err
+		}
+		if err := profileManager.Save(); err != nil {
+			return err
+		}
+		return o.app.Present(profileModel(p))
+	}
+
+	dirs := prism.FindInstances()
+	if len(dirs) == 0 {
+		return fmt.Errorf("no Prism Launcher instances found")
+	}
+
+	// Failures are reported, but do not stop the remaining instances from being imported
+	var result appModel.ProfileList
+	for _, dir := range dirs {
+		instance, err := prism.ReadInstance(dir)
+		if err == nil {
+			var p *profile.Profile
+			if p, err = o.importInstance(ctx, instance); err == nil {
+				result = append(result, profileModel(p))
+				continue
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "skipping %s: %s\n", dir, err)
+	}
+	if err := profileManager.Save(); err != nil {
+		return err
+	}
+	return o.app.Present(result)
+}
+
+// importInstance installs the version of the instance and creates a profile for it, named after the instance.
+func (o *importPrismOpts) importInstance(ctx context.Context, instance *prism.Instance) (*profile.Profile, error) {
+	name := profile.ToValidName(instance.Name)
+	profileManager := o.app.ProfileManager()
+	if _, err := profileManager.GetProfile(name); err == nil {
+		return nil, fmt.Errorf("%w: %s", profile.ErrNameInUse, name)
+	}
+
+	v, err := o.app.FindVersion(ctx, instance.Type, instance.GameVersion, instance.Loader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, instance.GameVersion)
+	}
+	version, err := o.app.InstallVersion(ctx, v, instance.Type)
+	if err != nil {
+		return nil, fmt.Errorf("installation failed: %w", err)
+	}
+	println("installed", version)
+
+	p, err := profileManager.CreateProfile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, name)
+	}
+	if err := o.setupProfile(p, instance); err != nil {
+		// Do not leave a partially imported profile behind
+		_ = profileManager.DeleteProfile(p.Name, true)
+		return nil, err
+	}
+
+	p.Type = instance.Type
+	p.Version = version
+	p.Loader = instance.Loader
+	return p, nil
+}
+
+// setupProfile points the profile at (or copies) the instance game directory and copies the instance settings.
+func (o *importPrismOpts) setupProfile(p *profile.Profile, instance *prism.Instance) error {
+	if err := os.MkdirAll(instance.GameDir, 0755); err != nil {
+		return err
+	}
+	if o.copy {
+		if err := util.CopyDir(instance.GameDir, p.Directory); err != nil {
+			return fmt.Errorf("failed to copy instance files: %w", err)
+		}
+	} else {
+		// The empty directory created for the profile is not used
+		if err := os.Remove(p.Directory); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		p.Directory = instance.GameDir
+	}
+
+	if len(instance.JvmArgs) == 0 && instance.MinMemory == 0 && instance.MaxMemory == 0 {
+		return nil
+	}
+	config := p.Config()
+	config.JvmArgs = instance.JvmArgs
+	config.MinMemory = instance.MinMemory
+	config.MaxMemory = instance.MaxMemory
+	return p.SaveConfig()
+}
@@ -0,0 +1,265 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/launcher"
+	"github.com/mworzala/mc/internal/pkg/platform"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+type importVanillaOpts struct {
+	app *cli.App
+
+	dir string
+}
+
+func newImportVanillaCmd(app *cli.App) *cobra.Command {
+	var o importVanillaOpts
+
+	cmd := &cobra.Command{
+		Use:   "import-vanilla [installation...]",
+		Short: "Import vanilla launcher installations as profiles",
+		Long: `Import vanilla launcher installations (from launcher_profiles.json) as profiles.
+
+Every installation is imported unless some are named. Versions are copied from the launcher versions directory
+where possible, and the profile uses the installation game directory. Java arguments and the java executable of
+the installation are copied to the profile config.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.app = app
+
+			// Stop downloads on interrupt, the partially imported profile is deleted
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer cancel()
+			err := o.importVanilla(ctx, args)
+			if ctx.Err() != nil {
+				cmd.SilenceErrors, cmd.SilenceUsage = true, true
+				return ctx.Err()
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&o.dir, "dir", "", "Vanilla launcher directory (default .minecraft)")
+
+	return cmd
+}
+
+func (o *importVanillaOpts) importVanilla(ctx context.Context, args []string) (err error) {
+	if o.dir == "" {
+		if o.dir, err = platform.MinecraftDir(); err != nil {
+			return err
+		}
+	}
+	launcherProfiles, err := launcher.ReadProfiles(o.dir)
+	if err != nil {
+		return err
+	}
+	if len(launcherProfiles.Installations) == 0 {
+		return fmt.Errorf("no installations found in %s", launcherProfiles.Path)
+	}
+
+	// Named installations may be given by key or name, and must all be imported. Otherwise failures are
+	// reported, but do not stop the remaining installations from being imported
+	var keys []string
+	for _, arg := range args {
+		key := findInstallation(launcherProfiles.Installations, arg)
+		if key == "" {
+			return fmt.Errorf("installation not found: %s", arg)
+		}
+		keys = append(keys, key)
+	}
+	if len(args) == 0 {
+		for key := range launcherProfiles.Installations {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+	}
+
+	var result appModel.ProfileList
+	for _, key := range keys {
+		p, err := o.importInstallation(ctx, key, launcherProfiles.Installations[key])
+		if err == nil {
+			result = append(result, profileModel(p))
+			continue
+		}
+		if ctx.Err() != nil || len(args) > 0 {
+			return err
+		}
+		_, _ = fmt.Fprintf(os.Stderr, "skipping %s: %s\n", key, err)
+	}
+
+	if err := o.app.ProfileManager().Save(); err != nil {
+		return err
+	}
+	return o.app.Present(result)
+}
+
+// importInstallation installs the version of the installation and creates a profile for it.
+func (o *importVanillaOpts) importInstallation(ctx context.Context, key string, install *launcher.Installation) (*profile.Profile, error) {
+	name := install.Name
+	if name == "" {
+		// The latest installations are unnamed, the launcher shows a translated name
+		name = key
+		if install.Type == launcher.TypeLatestRelease || install.Type == launcher.TypeLatestSnapshot {
+			name = install.Type
+		}
+	}
+	name = profile.ToValidName(name)
+	profileManager := o.app.ProfileManager()
+	if _, err := profileManager.GetProfile(name); err == nil {
+		return nil, fmt.Errorf("%w: %s", profile.ErrNameInUse, name)
+	}
+
+	typ, version, loader, err := o.installVersion(ctx, install)
+	if err != nil {
+		return nil, err
+	}
+	println("installed", version)
+
+	p, err := profileManager.CreateProfile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, name)
+	}
+	if err := o.setupProfile(p, install); err != nil {
+		// Do not leave a partially imported profile behind
+		_ = profileManager.DeleteProfile(p.Name, true)
+		return nil, err
+	}
+
+	p.Type = typ
+	p.Version = version
+	p.Loader = loader
+	return p, nil
+}
+
+// installVersion installs the version used by the installation, copying it from the launcher if present.
+func (o *importVanillaOpts) installVersion(ctx context.Context, install *launcher.Installation) (profile.Type, string, string, error) {
+	id := install.LastVersionId
+	if install.Type == launcher.TypeLatestRelease || install.Type == launcher.TypeLatestSnapshot || id == "" {
+		release, snapshot, err := o.app.VersionManager().LatestVersions(ctx)
+		if err != nil {
+			return 0, "", "", err
+		}
+		id = release
+		if install.Type == launcher.TypeLatestSnapshot {
+			id = snapshot
+		}
+		if id == "" {
+			return 0, "", "", fmt.Errorf("latest version is unknown, run `mc versions refresh`")
+		}
+	}
+
+	// Versions unknown to the launcher are assumed to be vanilla, eg if never launched
+	launcherVersions := path.Join(o.dir, "versions")
+	var spec gameModel.VersionSpec
+	err := util.ReadFile(path.Join(launcherVersions, id, fmt.Sprintf("%s.json", id)), &spec)
+	if errors.Is(err, fs.ErrNotExist) {
+		v, err := o.app.FindVersion(ctx, profile.Vanilla, id, "")
+		if err != nil {
+			return 0, "", "", fmt.Errorf("%w: %s", err, id)
+		}
+		if id, err = o.app.InstallVersion(ctx, v, profile.Vanilla); err != nil {
+			return 0, "", "", fmt.Errorf("installation failed: %w", err)
+		}
+		return profile.Vanilla, id, "", nil
+	} else if err != nil {
+		return 0, "", "", fmt.Errorf("failed to read version %s: %w", id, err)
+	}
+
+	// Forge style versions depend on libraries generated by their installer, so they are installed again
+	typ, loader := launcher.VersionType(&spec)
+	if typ == profile.Forge || typ == profile.NeoForge {
+		v, err := o.app.FindVersion(ctx, typ, spec.InheritsFrom, loader)
+		if err != nil {
+			return 0, "", "", fmt.Errorf("%w: %s", err, id)
+		}
+		if id, err = o.app.InstallVersion(ctx, v, typ); err != nil {
+			return 0, "", "", fmt.Errorf("installation failed: %w", err)
+		}
+		return typ, id, loader, nil
+	}
+
+	if err := launcher.CopyVersion(launcherVersions, path.Join(o.app.ConfigDir, "versions"), id); err != nil {
+		return 0, "", "", fmt.Errorf("failed to copy version %s: %w", id, err)
+	}
+	if err := o.app.Installer().Install(ctx, &gameModel.VersionInfo{Id: id}); err != nil {
+		return 0, "", "", fmt.Errorf("installation failed: %w", err)
+	}
+	return typ, id, loader, nil
+}
+
+// setupProfile points the profile at the installation game directory and copies the installation java settings.
+func (o *importVanillaOpts) setupProfile(p *profile.Profile, install *launcher.Installation) error {
+	gameDir := install.GameDir
+	if gameDir == "" {
+		gameDir = o.dir
+	}
+	if err := os.MkdirAll(gameDir, 0755); err != nil {
+		return err
+	}
+	// The empty directory created for the profile is not used
+	if err := os.Remove(p.Directory); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	p.Directory = gameDir
+
+	args, minMemory, maxMemory := launcher.ParseJavaArgs(install.JavaArgs)
+	if len(args) == 0 && minMemory == 0 && maxMemory == 0 && install.JavaDir == "" {
+		return nil
+	}
+	config := p.Config()
+	config.JvmArgs = args
+	config.MinMemory = minMemory
+	config.MaxMemory = maxMemory
+	if install.JavaDir != "" {
+		javaName, err := o.javaInstallation(install.JavaDir)
+		if err != nil {
+			return fmt.Errorf("failed to use java %s: %w", install.JavaDir, err)
+		}
+		config.Java = javaName
+	}
+	return p.SaveConfig()
+}
+
+// javaInstallation returns the name of the java installation with the given executable, discovering it if new.
+func (o *importVanillaOpts) javaInstallation(exec string) (string, error) {
+	javaManager := o.app.JavaManager()
+	for _, name := range javaManager.Installations() {
+		if install := javaManager.GetInstallation(name); install != nil && install.Path == exec {
+			return install.Name, nil
+		}
+	}
+
+	install, err := javaManager.Discover(exec)
+	if err != nil {
+		return "", err
+	}
+	return install.Name, javaManager.Save()
+}
+
+// findInstallation returns the key of the installation with the given key or name, or an empty string.
+func findInstallation(installs map[string]*launcher.Installation, name string) string {
+	if _, ok := installs[name]; ok {
+		return name
+	}
+	for key, install := range installs {
+		if strings.EqualFold(install.Name, name) {
+			return key
+		}
+	}
+	return ""
+}
@@ -37,6 +37,7 @@ func (o *listProfilesOpts) listProfiles() error {
 			Directory: profile.Directory,
 			Type:      appModel.ProfileTypes[profile.Type],
 			Version:   profile.Version,
+			Loader:    profile.Loader,
 		})
 	}
 
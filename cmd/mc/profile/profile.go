@@ -2,6 +2,8 @@ package profile
 
 import (
 	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/mworzala/mc/internal/pkg/profile"
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +14,25 @@ func NewProfileCmd(app *cli.App) *cobra.Command {
 	}
 
 	cmd.AddCommand(newListCmd(app))
+	cmd.AddCommand(newDeleteCmd(app))
+	cmd.AddCommand(newRenameCmd(app))
+	cmd.AddCommand(newCloneCmd(app))
+	cmd.AddCommand(newExportCmd(app))
+	cmd.AddCommand(newImportCmd(app))
+	cmd.AddCommand(newImportPrismCmd(app))
+	cmd.AddCommand(newImportVanillaCmd(app))
+	cmd.AddCommand(newExportVanillaCmd(app))
+	cmd.AddCommand(newExportMrpackCmd(app))
 
 	return cmd
 }
+
+func profileModel(p *profile.Profile) *appModel.Profile {
+	return &appModel.Profile{
+		Name:      p.Name,
+		Directory: p.Directory,
+		Type:      appModel.ProfileTypes[p.Type],
+		Version:   p.Version,
+		Loader:    p.Loader,
+	}
+}
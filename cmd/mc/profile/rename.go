@@ -0,0 +1,62 @@
+package profile
+
+import (
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	"github.com/spf13/cobra"
+)
+
+type renameProfileOpts struct {
+	app *cli.App
+
+	keepDir bool
+}
+
+func newRenameCmd(app *cli.App) *cobra.Command {
+	var o renameProfileOpts
+
+	cmd := &cobra.Command{
+		Use:     "rename",
+		Aliases: []string{"mv"},
+		Short:   "Rename a profile",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			o.app = app
+			return o.renameProfile(args)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.keepDir, "keep-dir", false, "Do not move the game directory to match the new name")
+
+	return cmd
+}
+
+func (o *renameProfileOpts) renameProfile(args []string) error {
+	profileManager := o.app.ProfileManager()
+	p, err := profileManager.GetProfile(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[0])
+	}
+
+	if o.app.GameManager().IsRunning(p.Name) {
+		return fmt.Errorf("profile %s is running, close the game first", p.Name)
+	}
+
+	p, err = profileManager.RenameProfile(p.Name, args[1], !o.keepDir)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, args[1])
+	}
+	if err := profileManager.Save(); err != nil {
+		return err
+	}
+
+	return o.app.Present(&appModel.Profile{
+		Name:      p.Name,
+		Directory: p.Directory,
+		Type:      appModel.ProfileTypes[p.Type],
+		Version:   p.Version,
+		Loader:    p.Loader,
+	})
+}
@@ -33,6 +33,7 @@ func NewRootCmd(app *cli.App) *cobra.Command {
 
 	cmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "json|yaml|template")
 	cmd.PersistentFlags().BoolVarP(&app.Config.NonInteractive, "non-interactive", "", false, "disable interactive prompts")
+	cmd.PersistentFlags().BoolVarP(&app.Config.Offline, "offline", "", app.Config.Offline, "never access the network, use only local caches")
 
 	cmd.AddCommand(account.NewAccountCmd(app))
 	cmd.AddCommand(java.NewJavaCmd(app))
@@ -40,7 +41,10 @@ func NewRootCmd(app *cli.App) *cobra.Command {
 	cmd.AddCommand(skin.NewSkinCmd(app))
 	cmd.AddCommand(newLaunchCmd(app))
 	cmd.AddCommand(newInstallCmd(app))
+	cmd.AddCommand(newGcCmd(app))
+	cmd.AddCommand(newDuCmd(app))
 	cmd.AddCommand(modrinth.NewModrinthCmd(app))
+	cmd.AddCommand(newVersionsCmd(app))
 	cmd.AddCommand(newVersionCmd(app))
 	cmd.AddCommand(newDebugCmd(app))
 
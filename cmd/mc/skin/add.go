@@ -87,7 +87,7 @@ func (o *addSkinOpts) execute(args []string) error {
 		return err
 	}
 
-	client := mojang.NewProfileClient(o.app.Build.Version, token)
+	client := mojang.NewProfileClient(o.app.Build.Version, o.app.HttpClient(), token)
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
@@ -67,7 +67,7 @@ func (o *applySkinOpts) execute(args []string) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 
-	client := mojang.NewProfileClient(o.app.Build.Version, token)
+	client := mojang.NewProfileClient(o.app.Build.Version, o.app.HttpClient(), token)
 
 	err = o.app.SkinManager().ApplySkin(ctx, client, skin)
 	if err != nil {
@@ -15,13 +15,13 @@ func newVersionCmd(app *cli.App) *cobra.Command {
 		Use:    "version",
 		Hidden: true,
 		Args:   cobra.NoArgs,
-		RunE: func(_ *cobra.Command, _ []string) error {
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			state := "clean"
 			if app.Build.Modified {
 				state = "modified"
 			}
 
-			latestRelease, latestSnapshot, err := app.VersionManager().LatestVersions()
+			latestRelease, latestSnapshot, err := app.VersionManager().LatestVersions(cmd.Context())
 			if err != nil {
 				return err
 			}
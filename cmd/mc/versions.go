@@ -0,0 +1,122 @@
+package mc
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mworzala/mc/internal/pkg/cli"
+	appModel "github.com/mworzala/mc/internal/pkg/cli/model"
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+type versionsOpts struct {
+	app *cli.App
+
+	snapshots    bool
+	experimental bool
+	old          bool
+	fabric       bool
+	since        string
+}
+
+func newVersionsCmd(app *cli.App) *cobra.Command {
+	var o versionsOpts
+
+	cmd := &cobra.Command{
+		Use:   "versions",
+		Short: "List available Minecraft versions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			o.app = app
+			return o.listVersions(cmd)
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.snapshots, "snapshots", false, "Include snapshots")
+	cmd.Flags().BoolVar(&o.experimental, "experimental", false, "Include experimental versions (eg combat tests)")
+	cmd.Flags().BoolVar(&o.old, "old", false, "Include old alpha and beta versions")
+	cmd.Flags().BoolVar(&o.fabric, "fabric", false, "Only show versions supported by fabric")
+	cmd.Flags().StringVar(&o.since, "since", "", "Only show versions released on or after the given date (YYYY-MM-DD)")
+
+	cmd.AddCommand(newVersionsRefreshCmd(app))
+
+	return cmd
+}
+
+func newVersionsRefreshCmd(app *cli.App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "refresh",
+		Short: "Check every version manifest for updates, ignoring the cache ttl",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if app.Config.Offline {
+				return util.ErrOffline
+			}
+
+			var result appModel.ManifestRefresh
+			var failed []string
+			for _, status := range app.VersionManager().Refresh(cmd.Context()) {
+				entry := &appModel.ManifestSourceStatus{Name: status.Name, Status: status.Status}
+				if status.Err != nil {
+					entry.Error = status.Err.Error()
+					failed = append(failed, status.Name)
+				}
+				result = append(result, entry)
+			}
+			if err := app.Present(result); err != nil {
+				return err
+			}
+
+			if len(failed) > 0 {
+				return fmt.Errorf("failed to refresh: %s", strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func (o *versionsOpts) listVersions(cmd *cobra.Command) error {
+	var since time.Time
+	if o.since != "" {
+		var err error
+		if since, err = time.Parse(time.DateOnly, o.since); err != nil {
+			return fmt.Errorf("invalid date: %s", o.since)
+		}
+	}
+
+	versionManager := o.app.VersionManager()
+	fabricLoader := versionManager.DefaultFabricLoader()
+
+	var result appModel.GameVersionList
+	for _, v := range versionManager.Versions(cmd.Context()) {
+		if v.Experimental && !o.experimental {
+			continue
+		}
+		switch v.Type {
+		case gameModel.VersionTypeSnapshot:
+			if !o.snapshots && !v.Experimental {
+				continue
+			}
+		case gameModel.VersionTypeOldBeta, gameModel.VersionTypeOldAlpha:
+			if !o.old {
+				continue
+			}
+		}
+		if v.ReleaseTime.Before(since) {
+			continue
+		}
+
+		info := &appModel.GameVersionInfo{Id: v.Id, Type: v.Type, ReleaseTime: v.ReleaseTime}
+		if versionManager.FabricSupported(v.Id) {
+			info.FabricLoader = fabricLoader
+		} else if o.fabric {
+			continue
+		}
+		result = append(result, info)
+	}
+
+	return o.app.Present(result)
+}
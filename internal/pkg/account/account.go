@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"path"
 	"strings"
@@ -60,14 +61,16 @@ type Manager interface {
 }
 
 type fileManager struct {
-	Path     string   `json:"-"`
-	Keychain Keychain `json:"-"`
+	Path     string       `json:"-"`
+	Keychain Keychain     `json:"-"`
+	Offline  bool         `json:"-"`
+	Client   *http.Client `json:"-"`
 
 	Default     string              `json:"default"`
 	AccountData map[string]*Account `json:"accounts"`
 }
 
-func NewManager(dataDir string, config *config.Config) (Manager, error) {
+func NewManager(dataDir string, config *config.Config, client *http.Client) (Manager, error) {
 
 	keychain := NewKeychain(dataDir, config.UseSystemKeyring)
 
@@ -78,6 +81,8 @@ func NewManager(dataDir string, config *config.Config) (Manager, error) {
 			Path:        accountsFile,
 			AccountData: make(map[string]*Account),
 			Keychain:    keychain,
+			Offline:     config.Offline,
+			Client:      client,
 		}, nil
 	}
 	f, err := os.Open(accountsFile)
@@ -91,6 +96,8 @@ func NewManager(dataDir string, config *config.Config) (Manager, error) {
 		Path:        accountsFile,
 		AccountData: make(map[string]*Account),
 		Keychain:    keychain,
+		Offline:     config.Offline,
+		Client:      client,
 	}
 	if err := json.NewDecoder(f).Decode(&manager); err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", accountsFileName, err)
@@ -145,6 +152,15 @@ func (m *fileManager) GetAccountToken(value string) (string, error) {
 		return "", fmt.Errorf("failed to get credentials: %w", err)
 	}
 
+	// Offline, the cached token is the best we can do. An expired token is still
+	// enough to launch into singleplayer.
+	if m.Offline {
+		if credentials.AccessToken == "" {
+			return "", fmt.Errorf("%w: no cached access token for %s", util.ErrOffline, account.Profile.Username)
+		}
+		return credentials.AccessToken, nil
+	}
+
 	// Update the credentials if necessary
 	changed, err := m.updateCredentialsMso(credentials)
 	if err != nil {
@@ -160,19 +176,23 @@ func (m *fileManager) GetAccountToken(value string) (string, error) {
 }
 
 func (m *fileManager) LoginMicrosoft(promptCallback MSOPromptCallback) (*Account, error) {
+	if m.Offline {
+		return nil, fmt.Errorf("%w: microsoft login", util.ErrOffline)
+	}
+
 	var account Account
 	var msoTokenData MicrosoftTokenData
 	account.Type = Microsoft
 	account.Source = &msoTokenData
 
 	// MSO Device Code
-	data, err := auth.BeginDeviceCodeAuth()
+	data, err := auth.BeginDeviceCodeAuth(m.Client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin device code auth: %w", err)
 	}
 	promptCallback(data.VerificationURL, data.UserCode)
 
-	msoToken, err := auth.PollDeviceCodeAuth(data)
+	msoToken, err := auth.PollDeviceCodeAuth(m.Client, data)
 	if err != nil {
 		return nil, fmt.Errorf("failed while polling device code auth: %w", err)
 	}
@@ -184,7 +204,7 @@ func (m *fileManager) LoginMicrosoft(promptCallback MSOPromptCallback) (*Account
 	msoTokenData.UserHash = userHash
 
 	// Fetch minecraft profile
-	profile, err := auth.GetMinecraftProfile(accessToken)
+	profile, err := auth.GetMinecraftProfile(m.Client, accessToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch minecraft profile: %w", err)
 	}
@@ -234,7 +254,7 @@ func (m *fileManager) updateCredentialsMso(credentials *Credentials) (bool, erro
 	}
 
 	// Refresh the credentials
-	msoToken, err := auth.RefreshMsoToken(credentials.RefreshToken)
+	msoToken, err := auth.RefreshMsoToken(m.Client, credentials.RefreshToken)
 	if err != nil {
 		return false, fmt.Errorf("failed to refresh MSO token: %w", err)
 	}
@@ -253,7 +273,7 @@ func (m *fileManager) updateCredentialsMso(credentials *Credentials) (bool, erro
 // createMsoCredentials creates a new minecraft access token from an MSO access token.
 func (m *fileManager) createCredentialsMso(msoAccessToken string) (xblUserHash string, accessToken string, tokenExpiration time.Time, err error) {
 	// Xbox Live
-	xblToken, err := auth.XboxLiveAuth(msoAccessToken)
+	xblToken, err := auth.XboxLiveAuth(m.Client, msoAccessToken)
 	if err != nil {
 		err = fmt.Errorf("failed to authenticate with Xbox Live: %w", err)
 		return
@@ -261,14 +281,14 @@ func (m *fileManager) createCredentialsMso(msoAccessToken string) (xblUserHash s
 	xblUserHash = xblToken.UserHash
 
 	// XSTS
-	xstsToken, err := auth.XSTSAuth(xblToken.AccessToken)
+	xstsToken, err := auth.XSTSAuth(m.Client, xblToken.AccessToken)
 	if err != nil {
 		err = fmt.Errorf("failed to authenticate with XSTS: %w", err)
 		return
 	}
 
 	// Minecraft Auth
-	mcToken, err := auth.MinecraftAuthMSO(xstsToken.AccessToken, xblToken.UserHash)
+	mcToken, err := auth.MinecraftAuthMSO(m.Client, xstsToken.AccessToken, xblToken.UserHash)
 	if err != nil {
 		err = fmt.Errorf("failed to authenticate with Minecraft: %w", err)
 		return
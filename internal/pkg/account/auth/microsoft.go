@@ -45,11 +45,11 @@ const (
 	errorAuthorizationPending deviceCodePollError = "authorization_pending"
 )
 
-func BeginDeviceCodeAuth() (*DeviceCodeData, error) {
+func BeginDeviceCodeAuth(client *http.Client) (*DeviceCodeData, error) {
 	endpoint := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/devicecode", msoTenant)
 	body := fmt.Sprintf("client_id=%s&scope=%s", url.QueryEscape(msoClientId), url.QueryEscape(msoScope))
 
-	res, err := http.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(body))
+	res, err := client.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +66,7 @@ func BeginDeviceCodeAuth() (*DeviceCodeData, error) {
 	return &payload, nil
 }
 
-func PollDeviceCodeAuth(data *DeviceCodeData) (*MsoTokenData, error) {
+func PollDeviceCodeAuth(client *http.Client, data *DeviceCodeData) (*MsoTokenData, error) {
 	endpoint := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", msoTenant)
 	body := fmt.Sprintf("grant_type=%s&client_id=%s&device_code=%s",
 		url.QueryEscape(msoGrantType), url.QueryEscape(msoClientId), url.QueryEscape(data.DeviceCode))
@@ -74,7 +74,7 @@ func PollDeviceCodeAuth(data *DeviceCodeData) (*MsoTokenData, error) {
 	expiry := time.Now().Add(time.Duration(data.ExpiresIn) * time.Second)
 	//todo probably could use a timeout context to handle the expiration here (more accurately)
 	for time.Now().Before(expiry) {
-		res, err := http.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(body))
+		res, err := client.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(body))
 		if err != nil {
 			return nil, err
 		}
@@ -113,12 +113,12 @@ func PollDeviceCodeAuth(data *DeviceCodeData) (*MsoTokenData, error) {
 // Microsoft OAuth (refresh token flow)
 // https://learn.microsoft.com/en-us/azure/active-directory/develop/v2-oauth2-auth-code-flow#refresh-the-access-token
 
-func RefreshMsoToken(refreshToken string) (*MsoTokenData, error) {
+func RefreshMsoToken(client *http.Client, refreshToken string) (*MsoTokenData, error) {
 	endpoint := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", msoTenant)
 	body := fmt.Sprintf("client_id=%s&scope=%s&refresh_token=%s&grant_type=refresh_token",
 		url.QueryEscape(msoClientId), url.QueryEscape(msoScope), url.QueryEscape(refreshToken))
 
-	res, err := http.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(body))
+	res, err := client.Post(endpoint, "application/x-www-form-urlencoded", strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +152,7 @@ type xboxLiveAuthResponse struct {
 	Token string `json:"Token"`
 }
 
-func XboxLiveAuth(msoAccessToken string) (*XboxLiveToken, error) {
+func XboxLiveAuth(client *http.Client, msoAccessToken string) (*XboxLiveToken, error) {
 	endpoint := "https://user.auth.xboxlive.com/user/authenticate"
 	body := fmt.Sprintf(`{
 "Properties": {
@@ -164,7 +164,7 @@ func XboxLiveAuth(msoAccessToken string) (*XboxLiveToken, error) {
 "TokenType": "JWT"
 }`, msoAccessToken)
 
-	res, err := http.Post(endpoint, "application/json", strings.NewReader(body))
+	res, err := client.Post(endpoint, "application/json", strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -192,7 +192,7 @@ type XSTSToken struct {
 	AccessToken string `json:"Token"`
 }
 
-func XSTSAuth(xblToken string) (*XSTSToken, error) {
+func XSTSAuth(client *http.Client, xblToken string) (*XSTSToken, error) {
 	endpoint := "https://xsts.auth.xboxlive.com/xsts/authorize"
 	body := fmt.Sprintf(`{
 "Properties": {
@@ -203,7 +203,7 @@ func XSTSAuth(xblToken string) (*XSTSToken, error) {
 "TokenType": "JWT"
 }`, xblToken)
 
-	res, err := http.Post(endpoint, "application/json", strings.NewReader(body))
+	res, err := client.Post(endpoint, "application/json", strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -15,14 +15,14 @@ type MinecraftToken struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
-func MinecraftAuthMSO(xstsToken, userHash string) (*MinecraftToken, error) {
+func MinecraftAuthMSO(client *http.Client, xstsToken, userHash string) (*MinecraftToken, error) {
 	endpoint := "https://api.minecraftservices.com/authentication/login_with_xbox"
 	body := fmt.Sprintf(`{
 "identityToken": "XBL3.0 x=%s;%s",
 "ensureLegacyEnabled": true
 }`, userHash, xstsToken)
 
-	res, err := http.Post(endpoint, "application/json", strings.NewReader(body))
+	res, err := client.Post(endpoint, "application/json", strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -45,14 +45,14 @@ type MinecraftProfile struct {
 	Username string `json:"name"`
 }
 
-func GetMinecraftProfile(accessToken string) (*MinecraftProfile, error) {
+func GetMinecraftProfile(client *http.Client, accessToken string) (*MinecraftProfile, error) {
 	req, err := http.NewRequest(http.MethodGet, "https://api.minecraftservices.com/minecraft/profile", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse url: %w", err)
 	}
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"errors"
+	"net/http"
 	"strings"
 
 	"github.com/mworzala/mc/internal/pkg/account"
@@ -9,9 +10,11 @@ import (
 	"github.com/mworzala/mc/internal/pkg/config"
 	"github.com/mworzala/mc/internal/pkg/game"
 	"github.com/mworzala/mc/internal/pkg/java"
+	"github.com/mworzala/mc/internal/pkg/network"
 	"github.com/mworzala/mc/internal/pkg/platform"
 	"github.com/mworzala/mc/internal/pkg/profile"
 	"github.com/mworzala/mc/internal/pkg/skin"
+	"github.com/mworzala/mc/internal/pkg/util"
 	"github.com/spf13/viper"
 )
 
@@ -30,6 +33,7 @@ type App struct {
 	Output    output.Format //todo migrate
 	Config    *config.Config
 
+	httpClient     *http.Client
 	accountManager account.Manager
 	javaManager    java.Manager
 	versionManager *game.VersionManager
@@ -76,10 +80,23 @@ func (a *App) readConfig() {
 	}
 }
 
+// HttpClient returns the client which should be used for all network access.
+func (a *App) HttpClient() *http.Client {
+	if a.httpClient == nil {
+		var err error
+		a.httpClient, err = network.NewClient(a.Config.Network, util.MakeUserAgent(a.Build.Version), a.Config.Offline)
+		if err != nil {
+			a.Fatal(err)
+		}
+	}
+
+	return a.httpClient
+}
+
 func (a *App) AccountManager() account.Manager {
 	if a.accountManager == nil {
 		var err error
-		a.accountManager, err = account.NewManager(a.ConfigDir, a.Config)
+		a.accountManager, err = account.NewManager(a.ConfigDir, a.Config, a.HttpClient())
 		if err != nil {
 			a.Fatal(err)
 		}
@@ -91,7 +108,7 @@ func (a *App) AccountManager() account.Manager {
 func (a *App) JavaManager() java.Manager {
 	if a.javaManager == nil {
 		var err error
-		a.javaManager, err = java.NewManager(a.ConfigDir)
+		a.javaManager, err = java.NewManager(a.ConfigDir, a.Config, a.HttpClient())
 		if err != nil {
 			a.Fatal(err)
 		}
@@ -103,7 +120,7 @@ func (a *App) JavaManager() java.Manager {
 func (a *App) VersionManager() *game.VersionManager {
 	if a.versionManager == nil {
 		var err error
-		a.versionManager, err = game.NewVersionManager(a.ConfigDir)
+		a.versionManager, err = game.NewVersionManager(a.ConfigDir, a.Config, a.HttpClient())
 		if err != nil {
 			a.Fatal(err)
 		}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mworzala/mc/internal/pkg/game/forge"
+	"github.com/mworzala/mc/internal/pkg/game/install"
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/profile"
+)
+
+var ErrUnresolvableVersion = errors.New("version cannot be resolved")
+
+// Installer returns a new installer writing to the data directory.
+func (a *App) Installer() *install.Installer {
+	installer := install.NewInstaller(a.ConfigDir, a.HttpClient(), a.Config.Offline, a.VersionManager().FindVanilla)
+	installer.SetCacheRoots(a.Config.CacheRoots, a.Config.CacheLink)
+	installer.SetManifestVersions(a.VersionManager().IsManifestVersion)
+	return installer
+}
+
+// FindVersion resolves the version used by a profile of the given type, using the default loader version
+// if loader is empty. Custom versions cannot be resolved, they are installed from their spec.
+func (a *App) FindVersion(ctx context.Context, typ profile.Type, gameVersion, loader string) (*gameModel.VersionInfo, error) {
+	versionManager := a.VersionManager()
+	switch typ {
+	case profile.Vanilla:
+		return versionManager.FindVanilla(ctx, gameVersion)
+	case profile.Fabric:
+		if loader == "" {
+			loader = versionManager.DefaultFabricLoader()
+		}
+		return versionManager.FindFabric(ctx, gameVersion, loader)
+	case profile.Quilt:
+		if loader == "" {
+			loader = versionManager.DefaultQuiltLoader()
+		}
+		return versionManager.FindQuilt(ctx, gameVersion, loader)
+	case profile.Forge:
+		if loader == "" {
+			loader = versionManager.DefaultForgeLoader(gameVersion)
+		}
+		return versionManager.FindForge(ctx, gameVersion, loader)
+	case profile.NeoForge:
+		if loader == "" {
+			loader = versionManager.DefaultNeoForgeLoader(gameVersion)
+		}
+		return versionManager.FindNeoForge(ctx, gameVersion, loader)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnresolvableVersion, gameVersion)
+}
+
+// InstallVersion installs the given version for a profile of the given type, returning the id of the
+// installed version. Forge style versions are created by running their installer with the default java.
+func (a *App) InstallVersion(ctx context.Context, v *gameModel.VersionInfo, typ profile.Type) (string, error) {
+	installer := a.Installer()
+	if typ != profile.Forge && typ != profile.NeoForge {
+		if err := installer.Install(ctx, v); err != nil {
+			return "", err
+		}
+		return v.Id, nil
+	}
+
+	// Forge (and NeoForge) install processors must be run with java
+	javaManager := a.JavaManager()
+	javaInstall := javaManager.GetInstallation(javaManager.GetDefault())
+	if javaInstall == nil {
+		return "", fmt.Errorf("no default java installation is set")
+	}
+	return forge.Install(ctx, a.ConfigDir, installer, v, javaInstall.Path)
+}
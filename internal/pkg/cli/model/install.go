@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/gosuri/uitable"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+type InstallPlanFile struct {
+	Kind    string
+	Path    string
+	Url     string
+	Size    int64
+	Present bool
+	Cached  bool
+}
+
+type InstallPlan struct {
+	Version      string
+	Files        []*InstallPlanFile
+	DownloadSize int64
+}
+
+func (p *InstallPlan) String() string {
+	table := uitable.New()
+	table.AddRow("KIND", "PATH", "SIZE", "STATUS")
+
+	// Asset objects are summarized, there are thousands of them
+	var objects, missingObjects, cachedObjects int
+	var objectsSize int64
+	for _, file := range p.Files {
+		if file.Kind == "asset_object" {
+			objects++
+			if file.Cached {
+				cachedObjects++
+			} else if !file.Present {
+				missingObjects++
+				objectsSize += file.Size
+			}
+			continue
+		}
+
+		size, status := "?", "missing"
+		if file.Size > 0 {
+			size = util.FormatBytes(file.Size)
+		}
+		if file.Present {
+			status = "present"
+		} else if file.Cached {
+			status = "cached"
+		}
+		table.AddRow(file.Kind, file.Path, size, status)
+	}
+	if objects > 0 {
+		status := fmt.Sprintf("%d missing", missingObjects)
+		if cachedObjects > 0 {
+			status = fmt.Sprintf("%s, %d cached", status, cachedObjects)
+		}
+		table.AddRow("asset_object", fmt.Sprintf("(%d files)", objects), util.FormatBytes(objectsSize), status)
+	}
+
+	return fmt.Sprintf("%s\n\n%s to download for %s", table.String(), util.FormatBytes(p.DownloadSize), p.Version)
+}
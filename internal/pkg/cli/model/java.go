@@ -7,9 +7,13 @@ import (
 )
 
 type JavaInstallation struct {
-	Name    string
-	Path    string
-	Version int
+	Name        string
+	Path        string
+	Arch        string
+	Version     int
+	Vendor      string
+	FullVersion string
+	Broken      bool
 }
 
 func (i *JavaInstallation) String() string {
@@ -20,10 +24,19 @@ type JavaInstallationList []*JavaInstallation
 
 func (l JavaInstallationList) String() string {
 	table := uitable.New()
-	table.AddRow("NAME", "EXECUTABLE PATH")
+	table.AddRow("NAME", "VERSION", "VENDOR", "STATUS", "EXECUTABLE PATH")
 	for _, install := range l {
-		//todo -o wide would output the arch and version also
-		table.AddRow(install.Name, install.Path)
+		version, vendor, status := install.FullVersion, install.Vendor, "ok"
+		if version == "" {
+			version = fmt.Sprintf("%d", install.Version)
+		}
+		if vendor == "" {
+			vendor = "-"
+		}
+		if install.Broken {
+			status = "broken"
+		}
+		table.AddRow(install.Name, version, vendor, status, install.Path)
 	}
 	return table.String()
 }
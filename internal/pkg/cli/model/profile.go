@@ -9,14 +9,22 @@ import (
 type ProfileType string
 
 const (
-	ProfileTypeVanilla ProfileType = "vanilla"
-	ProfileTypeFabric  ProfileType = "fabric"
+	ProfileTypeVanilla  ProfileType = "vanilla"
+	ProfileTypeFabric   ProfileType = "fabric"
+	ProfileTypeForge    ProfileType = "forge"
+	ProfileTypeNeoForge ProfileType = "neoforge"
+	ProfileTypeQuilt    ProfileType = "quilt"
+	ProfileTypeCustom   ProfileType = "custom"
 )
 
 var ProfileTypes = []ProfileType{
 	ProfileType("unknown"),
 	ProfileTypeVanilla,
 	ProfileTypeFabric,
+	ProfileTypeForge,
+	ProfileTypeNeoForge,
+	ProfileTypeQuilt,
+	ProfileTypeCustom,
 }
 
 type Profile struct {
@@ -25,6 +33,7 @@ type Profile struct {
 
 	Type    ProfileType
 	Version string
+	Loader  string
 }
 
 func (p *Profile) String() string {
@@ -35,10 +44,10 @@ type ProfileList []*Profile
 
 func (l ProfileList) String() string {
 	table := uitable.New()
-	table.AddRow("NAME", "TYPE", "VERSION")
+	table.AddRow("NAME", "TYPE", "VERSION", "LOADER")
 	for _, profile := range l {
 		//todo -o wide
-		table.AddRow(profile.Name, profile.Type, profile.Version)
+		table.AddRow(profile.Name, profile.Type, profile.Version, profile.Loader)
 	}
 	return table.String()
 }
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/gosuri/uitable"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+type StorageEntry struct {
+	Kind string
+	Path string
+	Size int64
+}
+
+type GarbageCollection struct {
+	DryRun  bool
+	Removed []*StorageEntry
+	Total   int64
+}
+
+func (gc *GarbageCollection) String() string {
+	if len(gc.Removed) == 0 {
+		return "nothing to remove"
+	}
+
+	var kinds []string
+	counts, sizes := map[string]int{}, map[string]int64{}
+	for _, entry := range gc.Removed {
+		if _, ok := counts[entry.Kind]; !ok {
+			kinds = append(kinds, entry.Kind)
+		}
+		counts[entry.Kind]++
+		sizes[entry.Kind] += entry.Size
+	}
+
+	table := uitable.New()
+	table.AddRow("KIND", "COUNT", "SIZE")
+	for _, kind := range kinds {
+		table.AddRow(kind, counts[kind], util.FormatBytes(sizes[kind]))
+	}
+
+	verb := "removed"
+	if gc.DryRun {
+		verb = "would remove"
+	}
+	return fmt.Sprintf("%s\n\n%s %s", table.String(), verb, util.FormatBytes(gc.Total))
+}
+
+type DiskUsageEntry struct {
+	Name string
+	Path string
+	Size int64
+}
+
+type DiskUsage struct {
+	Profiles    []*DiskUsageEntry
+	Shared      []*DiskUsageEntry
+	Reclaimable int64
+	Total       int64
+}
+
+func (du *DiskUsage) String() string {
+	table := uitable.New()
+	table.AddRow("PROFILE", "SIZE", "PATH")
+	for _, entry := range du.Profiles {
+		table.AddRow(entry.Name, util.FormatBytes(entry.Size), entry.Path)
+	}
+	table.AddRow("")
+	table.AddRow("SHARED", "SIZE", "PATH")
+	for _, entry := range du.Shared {
+		table.AddRow(entry.Name, util.FormatBytes(entry.Size), entry.Path)
+	}
+	return fmt.Sprintf("%s\n\ntotal %s, %s reclaimable with 'mc gc'",
+		table.String(), util.FormatBytes(du.Total), util.FormatBytes(du.Reclaimable))
+}
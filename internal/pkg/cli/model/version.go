@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/gosuri/uitable"
 )
 
 type Version struct {
@@ -45,3 +46,50 @@ func (v *Version) String() string {
 		minecraft %s (%s)`,
 		v.Tag, date, v.Tag, v.Game.Release, v.Game.Snapshot)
 }
+
+type GameVersionInfo struct {
+	Id          string
+	Type        string
+	ReleaseTime time.Time
+	// FabricLoader is the latest fabric loader if fabric is available for the version
+	FabricLoader string
+}
+
+type GameVersionList []*GameVersionInfo
+
+func (l GameVersionList) String() string {
+	table := uitable.New()
+	table.AddRow("VERSION", "TYPE", "RELEASED", "FABRIC")
+	for _, v := range l {
+		released, fabric := "-", "-"
+		if !v.ReleaseTime.IsZero() {
+			released = v.ReleaseTime.Format("2006-01-02")
+		}
+		if v.FabricLoader != "" {
+			fabric = v.FabricLoader
+		}
+		table.AddRow(v.Id, v.Type, released, fabric)
+	}
+	return table.String()
+}
+
+type ManifestSourceStatus struct {
+	Name   string
+	Status string
+	Error  string
+}
+
+type ManifestRefresh []*ManifestSourceStatus
+
+func (l ManifestRefresh) String() string {
+	table := uitable.New()
+	table.AddRow("SOURCE", "STATUS", "ERROR")
+	for _, s := range l {
+		errorMessage := "-"
+		if s.Error != "" {
+			errorMessage = s.Error
+		}
+		table.AddRow(s.Name, s.Status, errorMessage)
+	}
+	return table.String()
+}
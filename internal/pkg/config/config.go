@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 // Config represents the app-global configuration options.
 //
 // Not all are loaded from the config file, some are set via global command flags.
@@ -9,8 +11,42 @@ type Config struct {
 	//todo output format
 
 	//NoColor      bool             `mapstructure:"no_color"` //todo
-	UseSystemKeyring bool             `mapstructure:"use_system_keyring"`
-	Experimental     ExperimentalOpts `mapstructure:"experimental"`
+	UseSystemKeyring bool `mapstructure:"use_system_keyring"`
+	// Offline disables all network access. Versions, libraries, assets and
+	// account tokens are only read from the local caches.
+	Offline bool `mapstructure:"offline"`
+	// ManifestTTL is how long the version manifests are used before checking for updates, eg "1h".
+	// Defaults to 10 minutes.
+	ManifestTTL time.Duration `mapstructure:"manifest_ttl"`
+	// CacheRoots are read-only directories with the same layout as the data directory (eg ~/.minecraft).
+	// Missing versions, libraries and assets are copied from them if their hash matches.
+	CacheRoots []string `mapstructure:"cache_roots"`
+	// CacheLink hardlinks files from the cache roots instead of copying them. Linked files share their
+	// contents with the cache root, so a later change to either is visible in both.
+	CacheLink    bool             `mapstructure:"cache_link"`
+	Network      NetworkOpts      `mapstructure:"network"`
+	Java         JavaOpts         `mapstructure:"java"`
+	Experimental ExperimentalOpts `mapstructure:"experimental"`
+}
+
+type NetworkOpts struct {
+	// Proxy is the url of the proxy to use for all requests, eg http://proxy:8080 or socks5://proxy:1080.
+	// If unset, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.
+	Proxy string `mapstructure:"proxy"`
+	// CaBundle is the path to a PEM file of additional trusted certificates
+	CaBundle string `mapstructure:"ca_bundle"`
+	// Timeout for connecting and receiving a response, eg "30s"
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Debug logs every request to stderr
+	Debug bool `mapstructure:"debug"`
+	// BandwidthLimit is the maximum combined download rate in bytes per second, or zero for no limit
+	BandwidthLimit int64 `mapstructure:"bandwidth_limit"`
+}
+
+type JavaOpts struct {
+	// AdoptiumUrl is the base url of the Adoptium API used to install temurin JDKs, eg for a mirror.
+	// Defaults to https://api.adoptium.net
+	AdoptiumUrl string `mapstructure:"adoptium_url"`
 }
 
 type ExperimentalOpts struct {
@@ -0,0 +1,105 @@
+package forge
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/mworzala/mc/internal/pkg/game/install"
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+var ErrUnsupportedInstaller = errors.New("unsupported installer format")
+
+// Install runs a Forge style installer jar without the installer gui.
+//
+// The version spec inside the installer is written to the versions directory and installed along with
+// its parent version. Then the install processors, which patch the vanilla client, are run using the
+// given java executable. The id of the installed version is returned.
+func Install(ctx context.Context, dataDir string, installer *install.Installer, v *gameModel.VersionInfo, javaPath string) (string, error) {
+	// Without an installer url the version is already installed (eg when offline), only validate it
+	if v.Url == "" {
+		return v.Id, installer.Install(ctx, v)
+	}
+
+	tempDir, err := os.MkdirTemp("", "mc-installer-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+
+	installerPath := path.Join(tempDir, "installer.jar")
+	if err := installer.Download(ctx, installerPath, util.FileDownload{Url: v.Url}); err != nil {
+		return "", fmt.Errorf("failed to download installer: %w", err)
+	}
+
+	zr, err := zip.OpenReader(installerPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open installer: %w", err)
+	}
+	defer zr.Close()
+
+	profileData, err := util.ReadZipFile(&zr.Reader, gameModel.InstallProfileFileName)
+	if err != nil {
+		return "", fmt.Errorf("failed to read install profile: %w", err)
+	}
+	var profile gameModel.InstallProfile
+	if err := json.Unmarshal(profileData, &profile); err != nil {
+		return "", fmt.Errorf("failed to read install profile: %w", err)
+	}
+	// Legacy installers (before 1.12.2) embed the version spec directly, which is not supported
+	if profile.Json == "" {
+		return "", ErrUnsupportedInstaller
+	}
+
+	specData, err := util.ReadZipFile(&zr.Reader, profile.Json)
+	if err != nil {
+		return "", fmt.Errorf("failed to read version spec: %w", err)
+	}
+	var spec gameModel.VersionSpec
+	if err := json.Unmarshal(specData, &spec); err != nil {
+		return "", fmt.Errorf("failed to read version spec: %w", err)
+	}
+
+	// Keep the install profile next to the spec, it references processor outputs which are used at runtime
+	versionDir := path.Join(dataDir, "versions", spec.Id)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path.Join(versionDir, fmt.Sprintf("%s.json", spec.Id)), specData, 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path.Join(versionDir, gameModel.InstallProfileFileName), profileData, 0644); err != nil {
+		return "", err
+	}
+
+	// Some artifacts (eg the forge jar itself) are only available inside the installer
+	librariesDir := path.Join(dataDir, "libraries")
+	if err := util.ExtractZip(&zr.Reader, "maven/", librariesDir, true); err != nil {
+		return "", err
+	}
+
+	if err := installer.Install(ctx, &gameModel.VersionInfo{Id: spec.Id}); err != nil {
+		return "", err
+	}
+	if err := installer.InstallLibraries(ctx, profile.Libraries); err != nil {
+		return "", fmt.Errorf("failed to install processor libraries: %w", err)
+	}
+
+	runner, err := newProcessorRunner(dataDir, installerPath, tempDir, &zr.Reader, &profile)
+	if err != nil {
+		return "", err
+	}
+	for _, processor := range profile.Processors {
+		if err := runner.run(ctx, javaPath, processor); err != nil {
+			return "", err
+		}
+	}
+
+	return spec.Id, nil
+}
@@ -0,0 +1,162 @@
+package forge
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/platform"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const clientSide = "client"
+
+// processorRunner runs the processors of an install profile, resolving their arguments
+// from the install profile data.
+type processorRunner struct {
+	librariesDir string
+	data         map[string]string
+}
+
+func newProcessorRunner(dataDir, installerPath, tempDir string, installer *zip.Reader, profile *gameModel.InstallProfile) (*processorRunner, error) {
+	r := &processorRunner{
+		librariesDir: path.Join(dataDir, "libraries"),
+		data: map[string]string{
+			"SIDE":              clientSide,
+			"MINECRAFT_JAR":     path.Join(dataDir, "versions", profile.Minecraft, fmt.Sprintf("%s.jar", profile.Minecraft)),
+			"MINECRAFT_VERSION": profile.Minecraft,
+			"ROOT":              dataDir,
+			"INSTALLER":         installerPath,
+			"LIBRARY_DIR":       path.Join(dataDir, "libraries"),
+		},
+	}
+
+	for key, value := range profile.Data {
+		if coordinate, ok := gameModel.ParseArtifactReference(value.Client); ok {
+			r.data[key] = r.libraryPath(coordinate)
+		} else if len(value.Client) >= 2 && strings.HasPrefix(value.Client, "'") && strings.HasSuffix(value.Client, "'") {
+			r.data[key] = value.Client[1 : len(value.Client)-1]
+		} else if value.Client != "" {
+			// A file inside the installer, which must be extracted for the processor to read
+			target, err := util.SafeJoin(tempDir, strings.TrimPrefix(value.Client, "/"))
+			if err != nil {
+				return nil, err
+			}
+			content, err := util.ReadZipFile(installer, value.Client)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read installer data %s: %w", key, err)
+			}
+			if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
+				return nil, err
+			}
+			if err := os.WriteFile(target, content, 0644); err != nil {
+				return nil, err
+			}
+			r.data[key] = target
+		}
+	}
+
+	return r, nil
+}
+
+func (r *processorRunner) run(ctx context.Context, javaPath string, processor *gameModel.Processor) error {
+	if len(processor.Sides) > 0 && !slices.Contains(processor.Sides, clientSide) {
+		return nil
+	}
+
+	// Skip the processor if it has already been run, eg when reinstalling
+	outputs := make(map[string]string)
+	for file, hash := range processor.Outputs {
+		outputs[r.resolve(file)] = r.resolve(hash)
+	}
+	if len(outputs) > 0 && r.verifyOutputs(outputs) == nil {
+		return nil
+	}
+
+	jarPath := r.libraryPath(processor.Jar)
+	mainClass, err := readMainClass(jarPath)
+	if err != nil {
+		return fmt.Errorf("failed to read processor %s: %w", processor.Jar, err)
+	}
+
+	classpath := []string{jarPath}
+	for _, library := range processor.Classpath {
+		classpath = append(classpath, r.libraryPath(library))
+	}
+
+	args := []string{"-cp", strings.Join(classpath, platform.ClasspathSeparator), mainClass}
+	for _, arg := range processor.Args {
+		args = append(args, r.resolve(arg))
+	}
+
+	output, err := exec.CommandContext(ctx, javaPath, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("processor %s failed: %w\n%s", processor.Jar, err, output)
+	}
+
+	if err := r.verifyOutputs(outputs); err != nil {
+		return fmt.Errorf("processor %s failed: %w", processor.Jar, err)
+	}
+	return nil
+}
+
+// resolve replaces a processor argument in the form `{KEY}` with the data value and `[artifact]` with
+// the path of the artifact. Any other argument is returned as is.
+func (r *processorRunner) resolve(arg string) string {
+	if len(arg) > 2 && strings.HasPrefix(arg, "{") && strings.HasSuffix(arg, "}") {
+		if value, ok := r.data[arg[1:len(arg)-1]]; ok {
+			return value
+		}
+	}
+	if coordinate, ok := gameModel.ParseArtifactReference(arg); ok {
+		return r.libraryPath(coordinate)
+	}
+	return arg
+}
+
+func (r *processorRunner) libraryPath(coordinate string) string {
+	return path.Join(r.librariesDir, gameModel.MavenPath(coordinate))
+}
+
+func (r *processorRunner) verifyOutputs(outputs map[string]string) error {
+	for file, expected := range outputs {
+		actual, err := util.FileSha1(file)
+		if err != nil {
+			return err
+		}
+		if actual != expected {
+			return fmt.Errorf("output hash mismatch for %s: %s != %s", path.Base(file), actual, expected)
+		}
+	}
+	return nil
+}
+
+// readMainClass reads the Main-Class attribute from the manifest of the given jar.
+func readMainClass(jarPath string) (string, error) {
+	zr, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	manifest, err := util.ReadZipFile(&zr.Reader, "META-INF/MANIFEST.MF")
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		if mainClass, ok := strings.CutPrefix(scanner.Text(), "Main-Class:"); ok {
+			return strings.TrimSpace(mainClass), nil
+		}
+	}
+	return "", fmt.Errorf("no main class in %s", path.Base(jarPath))
+}
@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path"
+	"testing"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessorArgs(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("data/client.lzma")
+	require.NoError(t, err)
+	_, _ = w.Write([]byte("binpatch"))
+	require.NoError(t, zw.Close())
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var profile gameModel.InstallProfile
+	profile.Minecraft = "1.20.1"
+	profile.Data = map[string]gameModel.SidedValue{
+		"MAPPINGS":   {Client: "[net.minecraft:client:1.20.1:mappings@txt]"},
+		"MC_SHA":     {Client: "'abc123'"},
+		"BINPATCH":   {Client: "/data/client.lzma"},
+		"SERVERONLY": {Server: "'x'"},
+	}
+
+	dataDir, tempDir := t.TempDir(), t.TempDir()
+	r, err := newProcessorRunner(dataDir, "installer.jar", tempDir, zr, &profile)
+	require.NoError(t, err)
+
+	require.Equal(t, path.Join(dataDir, "libraries/net/minecraft/client/1.20.1/client-1.20.1-mappings.txt"), r.resolve("{MAPPINGS}"))
+	require.Equal(t, "abc123", r.resolve("{MC_SHA}"))
+	require.Equal(t, path.Join(dataDir, "versions/1.20.1/1.20.1.jar"), r.resolve("{MINECRAFT_JAR}"))
+	require.Equal(t, "client", r.resolve("{SIDE}"))
+	require.Equal(t, path.Join(dataDir, "libraries/a/b/1/b-1.jar"), r.resolve("[a:b:1]"))
+	require.Equal(t, "--task", r.resolve("--task"))
+	require.Equal(t, "{SERVERONLY}", r.resolve("{SERVERONLY}"))
+
+	content, err := os.ReadFile(r.resolve("{BINPATCH}"))
+	require.NoError(t, err)
+	require.Equal(t, "binpatch", string(content))
+}
@@ -7,9 +7,20 @@ import (
 	"io/fs"
 	"os"
 	"path"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/platform"
 )
 
 type Manager interface {
+	// AddProcess records a newly started game process for the given profile.
+	//
+	// The Manager is not responsible for persisting the change, Save should be called afterwards.
+	AddProcess(profile string, pid int)
+	// IsRunning returns true if a game process recorded for the given profile is still alive.
+	IsRunning(profile string) bool
+
+	Save() error
 }
 
 var (
@@ -46,9 +57,32 @@ func NewManager(dataDir string) (Manager, error) {
 	return &manager, nil
 }
 
-//func (m *fileManager) updateProcesses() error {
-//
-//}
+func (m *fileManager) AddProcess(profile string, pid int) {
+	profile = strings.ToLower(profile)
+	m.Running[profile] = append(m.aliveProcesses(profile), int64(pid))
+}
+
+func (m *fileManager) IsRunning(profile string) bool {
+	return len(m.aliveProcesses(strings.ToLower(profile))) > 0
+}
+
+// aliveProcesses returns the recorded processes of a profile which are still running,
+// dropping any which have exited since.
+func (m *fileManager) aliveProcesses(profile string) []int64 {
+	var alive []int64
+	for _, pid := range m.Running[profile] {
+		if platform.IsProcessRunning(int(pid)) {
+			alive = append(alive, pid)
+		}
+	}
+
+	if len(alive) == 0 {
+		delete(m.Running, profile)
+	} else {
+		m.Running[profile] = alive
+	}
+	return alive
+}
 
 func (m *fileManager) Save() error {
 	f, err := os.OpenFile(m.Path, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
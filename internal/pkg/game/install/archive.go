@@ -0,0 +1,100 @@
+package install
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+// isVersionArchive returns true if the version is distributed as a zip archive containing the version spec
+// (and sometimes the client jar) rather than the spec itself, as is the case for some experimental versions.
+func isVersionArchive(v *gameModel.VersionInfo) bool {
+	u, err := url.Parse(v.Url)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(path.Ext(u.Path), ".zip")
+}
+
+// extractVersionArchive downloads the archive of the given version and extracts the version spec and client
+// jar into the version directory. Nothing is done if the version spec is already present.
+func (i *Installer) extractVersionArchive(ctx context.Context, v *gameModel.VersionInfo) error {
+	versionDir := path.Join(i.versionsDir, v.Id)
+	versionSpecPath := path.Join(versionDir, fmt.Sprintf("%s.json", v.Id))
+	if _, err := os.Stat(versionSpecPath); err == nil {
+		return nil
+	}
+
+	archivePath := path.Join(versionDir, fmt.Sprintf("%s.zip", v.Id))
+	if err := i.Download(ctx, archivePath, util.FileDownload{Url: v.Url, Sha1: v.Sha1}); err != nil {
+		return fmt.Errorf("failed to download version archive: %w", err)
+	}
+	defer os.Remove(archivePath)
+
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open version archive: %w", err)
+	}
+	defer archive.Close()
+
+	// Entries are matched by name only, some archives place them in a directory
+	var specFile, clientFile *zip.File
+	var jsonFiles []*zip.File
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		switch name := path.Base(f.Name); {
+		case name == fmt.Sprintf("%s.json", v.Id):
+			specFile = f
+		case name == fmt.Sprintf("%s.jar", v.Id):
+			clientFile = f
+		case path.Ext(name) == ".json":
+			jsonFiles = append(jsonFiles, f)
+		}
+	}
+	if specFile == nil && len(jsonFiles) == 1 {
+		specFile = jsonFiles[0]
+	}
+	if specFile == nil {
+		return fmt.Errorf("%w: no version spec in archive for %s", ErrInvalidSpec, v.Id)
+	}
+
+	specData, err := util.ReadZipFile(&archive.Reader, specFile.Name)
+	if err != nil {
+		return fmt.Errorf("failed to extract version spec: %w", err)
+	}
+	var spec gameModel.VersionSpec
+	if err := json.Unmarshal(specData, &spec); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSpec, err)
+	}
+	if spec.Id != v.Id {
+		return fmt.Errorf("%w: archive for %s contains version %s", ErrInvalidSpec, v.Id, spec.Id)
+	}
+
+	// The spec is written last, so that it is only present if the archive was fully extracted
+	if clientFile != nil {
+		data, err := util.ReadZipFile(&archive.Reader, clientFile.Name)
+		if err != nil {
+			return fmt.Errorf("failed to extract client: %w", err)
+		}
+		if spec.Downloads != nil && spec.Downloads.Client != nil && spec.Downloads.Client.Sha1 != "" {
+			if hash := fmt.Sprintf("%x", sha1.Sum(data)); hash != spec.Downloads.Client.Sha1 {
+				return fmt.Errorf("%w: client of %s has sha1 %s, expected %s", ErrInvalidSpec, v.Id, hash, spec.Downloads.Client.Sha1)
+			}
+		}
+		if err := os.WriteFile(path.Join(versionDir, fmt.Sprintf("%s.jar", v.Id)), data, 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(versionSpecPath, specData, 0644)
+}
@@ -0,0 +1,87 @@
+package install
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractVersionArchive(t *testing.T) {
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	for name, content := range map[string]string{
+		"1.19_deep_dark/1.19_deep_dark.json": `{"id": "1.19_deep_dark", "mainClass": "Main"}`,
+		"1.19_deep_dark/1.19_deep_dark.jar":  "client",
+	} {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	installer := NewInstaller(dataDir, server.Client(), false, nil)
+	v := &gameModel.VersionInfo{Id: "1.19_deep_dark", Url: server.URL + "/1_19_deep_dark.zip"}
+	require.True(t, isVersionArchive(v))
+	require.NoError(t, installer.extractVersionArchive(context.Background(), v))
+
+	versionDir := path.Join(dataDir, "versions", "1.19_deep_dark")
+	spec, err := os.ReadFile(path.Join(versionDir, "1.19_deep_dark.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(spec), `"mainClass": "Main"`)
+	client, err := os.ReadFile(path.Join(versionDir, "1.19_deep_dark.jar"))
+	require.NoError(t, err)
+	assert.Equal(t, "client", string(client))
+
+	// The archive itself is not kept
+	assert.NoFileExists(t, path.Join(versionDir, "1.19_deep_dark.zip"))
+}
+
+func TestExtractVersionArchiveInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"different id", `{"id": "1.19_other"}`},
+		{"client hash mismatch", `{"id": "1.19_deep_dark", "downloads": {"client": {"sha1": "0000000000000000000000000000000000000000"}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var archive bytes.Buffer
+			w := zip.NewWriter(&archive)
+			for name, content := range map[string]string{"1.19_deep_dark.json": tt.spec, "1.19_deep_dark.jar": "client"} {
+				f, err := w.Create(name)
+				require.NoError(t, err)
+				_, err = f.Write([]byte(content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, w.Close())
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(archive.Bytes())
+			}))
+			defer server.Close()
+
+			dataDir := t.TempDir()
+			installer := NewInstaller(dataDir, server.Client(), false, nil)
+			v := &gameModel.VersionInfo{Id: "1.19_deep_dark", Url: server.URL + "/1_19_deep_dark.zip"}
+			assert.ErrorIs(t, installer.extractVersionArchive(context.Background(), v), ErrInvalidSpec)
+			assert.NoFileExists(t, path.Join(dataDir, "versions", "1.19_deep_dark", "1.19_deep_dark.json"))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package install
+
+import (
+	"fmt"
+	"path"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+// LinkAssets reconstructs the named asset tree of a virtual or resources mapped asset index in dest
+// using the objects in the object store. Files are hardlinked where possible.
+func LinkAssets(assetsDir string, index *gameModel.AssetIndex, dest string) error {
+	objectsDir := path.Join(assetsDir, "objects")
+	for name, obj := range index.Objects {
+		target, err := util.SafeJoin(dest, name)
+		if err != nil {
+			return err
+		}
+
+		src := path.Join(objectsDir, obj.Hash[:2], obj.Hash)
+		if err := util.LinkOrCopyFile(src, target); err != nil {
+			return fmt.Errorf("failed to link asset %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+// SetCacheRoots sets the read-only directories searched for files before downloading them, eg ~/.minecraft.
+// They must have the same layout as the data directory (versions, libraries and assets/objects). Files are
+// copied from the cache roots unless link is set, in which case they are hardlinked where possible.
+func (i *Installer) SetCacheRoots(roots []string, link bool) {
+	i.cacheRoots = roots
+	i.cacheLink = link
+}
+
+// findCached returns the path of the given file in the first cache root which has a matching copy, or an
+// empty string. Files are matched by path and sha1, files without a known sha1 are never reused.
+func (i *Installer) findCached(file string, dl util.FileDownload) string {
+	if len(i.cacheRoots) == 0 || dl.Sha1 == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(i.configDir, file)
+	if err != nil || !filepath.IsLocal(rel) {
+		return ""
+	}
+
+	for _, root := range i.cacheRoots {
+		cached := filepath.Join(root, rel)
+		info, err := os.Stat(cached)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if dl.Size > 0 && info.Size() != dl.Size {
+			continue
+		}
+		if hash, err := util.FileSha1(cached); err != nil || hash != dl.Sha1 {
+			continue
+		}
+		return cached
+	}
+	return ""
+}
+
+// linkFromCache copies (or hardlinks if enabled) a missing file from the cache roots. It returns false if the
+// file still needs to be downloaded.
+func (i *Installer) linkFromCache(file string, dl util.FileDownload) bool {
+	if _, err := os.Stat(file); err == nil {
+		return false
+	}
+	cached := i.findCached(file, dl)
+	if cached == "" {
+		return false
+	}
+	if i.cacheLink {
+		return util.LinkOrCopyFile(cached, file) == nil
+	}
+	return util.CopyFile(cached, file) == nil
+}
@@ -0,0 +1,59 @@
+package install
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadFromCache(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("library"))
+	}))
+	defer server.Close()
+
+	dataDir, cacheDir := t.TempDir(), t.TempDir()
+	const library = "libraries/org/example/lib/1.0/lib-1.0.jar"
+	require.NoError(t, os.MkdirAll(path.Dir(path.Join(cacheDir, library)), 0755))
+	require.NoError(t, os.WriteFile(path.Join(cacheDir, library), []byte("library"), 0644))
+
+	installer := NewInstaller(dataDir, server.Client(), false, nil)
+	installer.SetCacheRoots([]string{cacheDir}, false)
+	dl := util.FileDownload{Url: server.URL, Sha1: fmt.Sprintf("%x", sha1.Sum([]byte("library"))), Size: 7}
+
+	// Matching hash is linked from the cache
+	require.NoError(t, installer.Download(context.Background(), path.Join(dataDir, library), dl))
+	assert.Equal(t, 0, requests)
+	assert.FileExists(t, path.Join(dataDir, library))
+
+	// Copies do not share their contents with the cache
+	require.NoError(t, os.WriteFile(path.Join(dataDir, library), []byte("written"), 0644))
+	content, err := os.ReadFile(path.Join(cacheDir, library))
+	require.NoError(t, err)
+	assert.Equal(t, "library", string(content), "cache must not be modified")
+
+	// Files without a known hash are never reused
+	require.NoError(t, os.Remove(path.Join(dataDir, library)))
+	require.NoError(t, installer.Download(context.Background(), path.Join(dataDir, library), util.FileDownload{Url: server.URL}))
+	assert.Equal(t, 1, requests)
+
+	// Modified files in the cache are ignored
+	require.NoError(t, os.Remove(path.Join(dataDir, library)))
+	require.NoError(t, os.WriteFile(path.Join(cacheDir, library), []byte("changed"), 0644))
+	require.NoError(t, installer.Download(context.Background(), path.Join(dataDir, library), dl))
+	assert.Equal(t, 2, requests)
+	content, err = os.ReadFile(path.Join(cacheDir, library))
+	require.NoError(t, err)
+	assert.Equal(t, "changed", string(content), "cache must not be modified")
+}
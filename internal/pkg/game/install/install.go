@@ -1,26 +1,44 @@
 package install
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
 
 	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
 	"github.com/mworzala/mc/internal/pkg/game/rule"
+	"github.com/mworzala/mc/internal/pkg/platform"
 	"github.com/mworzala/mc/internal/pkg/util"
 )
 
 var (
-	MinLauncherVersion            = 21
+	// Every version up to 1.13 (launcher version 21) is supported, older versions use minecraftArguments,
+	// legacy asset indexes and extracted natives
+	MinLauncherVersion            = 1
 	MaxLauncherVersion            = 21
 	ErrUnsupportedLauncherVersion = errors.New("unsupported launcher version")
+	ErrInvalidSpec                = errors.New("invalid version spec")
+	ErrVersionExists              = errors.New("version already exists")
 )
 
 type Installer struct {
 	configDir      string
-	getVersionFunc func(string) (*gameModel.VersionInfo, error)
+	client         *http.Client
+	offline        bool
+	getVersionFunc func(context.Context, string) (*gameModel.VersionInfo, error)
+	cacheRoots     []string
+	cacheLink      bool
+	// isManifestVersion returns true for ids of versions from the version manifest, which custom specs may not use
+	isManifestVersion func(string) bool
 
 	// Common directories
 	versionsDir  string
@@ -30,9 +48,13 @@ type Installer struct {
 	rules *rule.Evaluator
 }
 
-func NewInstaller(configDir string, getVersionFunc func(string) (*gameModel.VersionInfo, error)) *Installer {
+// NewInstaller creates an installer writing to the given config directory. If offline is set,
+// the installer will only validate that the required files are already present.
+func NewInstaller(configDir string, client *http.Client, offline bool, getVersionFunc func(context.Context, string) (*gameModel.VersionInfo, error)) *Installer {
 	return &Installer{
 		configDir:      configDir,
+		client:         client,
+		offline:        offline,
 		getVersionFunc: getVersionFunc,
 
 		versionsDir:  path.Join(configDir, "versions"),
@@ -43,32 +65,128 @@ func NewInstaller(configDir string, getVersionFunc func(string) (*gameModel.Vers
 	}
 }
 
-func (i *Installer) Install(v *gameModel.VersionInfo) error {
+func (i *Installer) Install(ctx context.Context, v *gameModel.VersionInfo) error {
 	versionDir := path.Join(i.versionsDir, v.Id)
 
+	if isVersionArchive(v) {
+		if err := i.extractVersionArchive(ctx, v); err != nil {
+			return err
+		}
+	}
+
 	// Download the version spec (or read it if it exists)
 	var spec gameModel.VersionSpec
 	versionSpecPath := path.Join(versionDir, fmt.Sprintf("%s.json", v.Id))
-	if err := util.ReadOrDownload(v.Id, versionSpecPath, util.FileDownload{Url: v.Url}, &spec); err != nil {
+	if err := i.readOrDownload(ctx, versionSpecPath, util.FileDownload{Url: v.Url, Sha1: v.Sha1}, &spec); err != nil {
 		return fmt.Errorf("failed to read version spec: %w", err)
 	}
 
 	// If there is an inherited version, install that
 	if spec.InheritsFrom != "" {
-		inherited, err := i.getVersionFunc(spec.InheritsFrom)
-		if err != nil {
+		inherited, err := i.getVersionFunc(ctx, spec.InheritsFrom)
+		if errors.Is(err, util.ErrOffline) {
+			return err
+		} else if err != nil {
 			return fmt.Errorf("inherited version not found: %s", spec.InheritsFrom)
 		}
 
-		if err := i.Install(inherited); err != nil {
+		if err := i.Install(ctx, inherited); err != nil {
 			return fmt.Errorf("error installing inherited version %s: %w", spec.InheritsFrom, err)
 		}
 	}
 
-	return i.InstallFromSpec(&spec)
+	return i.InstallFromSpec(ctx, &spec)
+}
+
+// SetManifestVersions sets the function used to check whether an id belongs to a version from the version
+// manifest. Custom specs cannot be installed with such an id, as they would replace the real version.
+func (i *Installer) SetManifestVersions(isManifestVersion func(id string) bool) {
+	i.isManifestVersion = isManifestVersion
+}
+
+// InstallFromJson installs a custom version spec from a local file or http(s) url. The spec is copied into
+// the versions directory and then installed along with its parent version. A different existing spec with the
+// same id is only replaced if force is set. The id of the installed version is returned.
+func (i *Installer) InstallFromJson(ctx context.Context, source string, force bool) (string, error) {
+	var data []byte
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		tempDir, err := os.MkdirTemp("", "mc-spec-*")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(tempDir)
+
+		tempFile := path.Join(tempDir, "spec.json")
+		if err := i.Download(ctx, tempFile, util.FileDownload{Url: source}); err != nil {
+			return "", fmt.Errorf("failed to download version spec: %w", err)
+		}
+		if data, err = os.ReadFile(tempFile); err != nil {
+			return "", err
+		}
+	} else {
+		var err error
+		if data, err = os.ReadFile(source); err != nil {
+			return "", fmt.Errorf("failed to read version spec: %w", err)
+		}
+	}
+
+	var spec gameModel.VersionSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidSpec, err)
+	}
+	if err := validateSpec(&spec); err != nil {
+		return "", err
+	}
+
+	if i.isManifestVersion != nil && i.isManifestVersion(spec.Id) {
+		return "", fmt.Errorf("%w: %s is not a custom version", ErrVersionExists, spec.Id)
+	}
+
+	// Other profiles may use the existing spec
+	versionDir := path.Join(i.versionsDir, spec.Id)
+	specPath := path.Join(versionDir, fmt.Sprintf("%s.json", spec.Id))
+	existing, err := os.ReadFile(specPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if err == nil && !bytes.Equal(existing, data) && !force {
+		return "", fmt.Errorf("%w: %s", ErrVersionExists, spec.Id)
+	}
+
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(specPath, data, 0644); err != nil {
+		return "", err
+	}
+
+	return spec.Id, i.Install(ctx, &gameModel.VersionInfo{Id: spec.Id})
 }
 
-func (i *Installer) InstallFromSpec(spec *gameModel.VersionSpec) error {
+// validateSpec checks that a custom version spec can be installed and launched.
+func validateSpec(spec *gameModel.VersionSpec) error {
+	if spec.Id == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidSpec)
+	}
+	if !filepath.IsLocal(spec.Id) || strings.ContainsAny(spec.Id, `/\`) {
+		return fmt.Errorf("%w: invalid id %s", ErrInvalidSpec, spec.Id)
+	}
+	if spec.InheritsFrom == spec.Id {
+		return fmt.Errorf("%w: %s inherits from itself", ErrInvalidSpec, spec.Id)
+	}
+	// The main class may come from the parent spec
+	if spec.InheritsFrom == "" && spec.MainClass == "" {
+		return fmt.Errorf("%w: missing main class", ErrInvalidSpec)
+	}
+	for _, library := range spec.Libraries {
+		if library.Name == "" && library.Downloads == nil {
+			return fmt.Errorf("%w: library without name", ErrInvalidSpec)
+		}
+	}
+	return nil
+}
+
+func (i *Installer) InstallFromSpec(ctx context.Context, spec *gameModel.VersionSpec) error {
 	// We assume support if the version is zero - fabric does not provide a version
 	if spec.MinimumLauncherVersion != 0 &&
 		(spec.MinimumLauncherVersion < MinLauncherVersion ||
@@ -79,13 +197,13 @@ func (i *Installer) InstallFromSpec(spec *gameModel.VersionSpec) error {
 	// Download client archive
 	if spec.Downloads != nil && spec.Downloads.Client != nil {
 		clientPath := path.Join(i.versionsDir, spec.Id, fmt.Sprintf("%s.jar", spec.Id))
-		if err := util.Download("", clientPath, *spec.Downloads.Client); err != nil {
+		if err := i.Download(ctx, clientPath, *spec.Downloads.Client); err != nil {
 			return fmt.Errorf("failed to download client: %w", err)
 		}
 	}
 
 	// Libraries
-	if err := i.downloadLibraries(spec.Libraries); err != nil {
+	if err := i.InstallLibraries(ctx, spec.Libraries); err != nil {
 		return err
 	}
 
@@ -93,20 +211,27 @@ func (i *Installer) InstallFromSpec(spec *gameModel.VersionSpec) error {
 	if index := spec.AssetIndex; index != nil {
 		var assetIndex gameModel.AssetIndex
 		assetIndexPath := path.Join(i.assetsDir, "indexes", fmt.Sprintf("%s.json", index.Id))
-		if err := util.ReadOrDownload("", assetIndexPath, index.FileDownload, &assetIndex); err != nil {
+		if err := i.readOrDownload(ctx, assetIndexPath, index.FileDownload, &assetIndex); err != nil {
 			return fmt.Errorf("failed to download asset index: %w", err)
 		}
 
 		// Asset objects
-		if err := i.downloadAssetObjects(index.TotalSize, &assetIndex); err != nil {
+		if err := i.downloadAssetObjects(ctx, &assetIndex); err != nil {
 			return err
 		}
+
+		// Legacy versions read assets by name. The resources directory is per profile, so it is created during launch
+		if assetIndex.Virtual {
+			if err := LinkAssets(i.assetsDir, &assetIndex, path.Join(i.assetsDir, "virtual", index.Id)); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Log config
 	if logging := spec.Logging; logging != nil {
 		logConfigPath := path.Join(i.assetsDir, "log_configs", logging.Client.File.Id)
-		if err := util.Download("", logConfigPath, logging.Client.File.FileDownload); err != nil {
+		if err := i.Download(ctx, logConfigPath, logging.Client.File.FileDownload); err != nil {
 			return fmt.Errorf("failed to download log config: %w", err)
 		}
 	}
@@ -114,28 +239,30 @@ func (i *Installer) InstallFromSpec(spec *gameModel.VersionSpec) error {
 	return nil
 }
 
-func (i *Installer) downloadLibraries(libraries []*gameModel.Library) error {
+// InstallLibraries downloads the given libraries which apply to the current platform.
+func (i *Installer) InstallLibraries(ctx context.Context, libraries []*gameModel.Library) error {
 	for _, library := range libraries {
 		if i.rules.Eval(library.Rules) == rule.Deny {
 			continue
 		}
 
 		if library.Downloads != nil { // Vanilla-type library
-			artifact := library.Downloads.Artifact
-			libraryPath := path.Join(i.librariesDir, artifact.Path)
-			if err := util.Download("", libraryPath, artifact.FileDownload); err != nil {
-				return fmt.Errorf("failed to download library %s: %w", library.Name, err)
+			// Libraries with only natives (eg lwjgl-platform) have no artifact
+			artifacts := []*gameModel.LibraryArtifact{&library.Downloads.Artifact, library.NativeArtifact(platform.Name, rule.HostArch())}
+			for _, artifact := range artifacts {
+				if artifact == nil || artifact.Path == "" {
+					continue
+				}
+				libraryPath := path.Join(i.librariesDir, artifact.Path)
+				if err := i.Download(ctx, libraryPath, artifact.FileDownload); err != nil {
+					return fmt.Errorf("failed to download library %s: %w", library.Name, err)
+				}
 			}
 		} else if library.Url != "" { // Direct maven library
-			parts := strings.Split(library.Name, ":")
-			groupId := parts[0]
-			artifactName := parts[1]
-			version := parts[2]
-
-			artifactPath := fmt.Sprintf("%s/%s/%s/%s-%s.jar", strings.ReplaceAll(groupId, ".", "/"), artifactName, version, artifactName, version)
+			artifactPath := library.ArtifactPath()
 			artifactUrl := fmt.Sprintf("%s/%s", strings.TrimSuffix(library.Url, "/"), artifactPath)
 
-			if err := util.Download("", path.Join(i.librariesDir, artifactPath), util.FileDownload{Url: artifactUrl}); err != nil {
+			if err := i.Download(ctx, path.Join(i.librariesDir, artifactPath), util.FileDownload{Url: artifactUrl}); err != nil {
 				return fmt.Errorf("failed to download library %s: %w", library.Name, err)
 			}
 		}
@@ -143,22 +270,44 @@ func (i *Installer) downloadLibraries(libraries []*gameModel.Library) error {
 	return nil
 }
 
-func (i *Installer) downloadAssetObjects(totalSize int64, index *gameModel.AssetIndex) error {
+func (i *Installer) downloadAssetObjects(ctx context.Context, index *gameModel.AssetIndex) error {
+	objectsPath := path.Join(i.assetsDir, "objects")
+
+	if i.offline {
+		// Only check for missing objects, never spawn downloads
+		for name, obj := range index.Objects {
+			objPath := path.Join(objectsPath, obj.Hash[:2], obj.Hash)
+			i.linkFromCache(objPath, util.FileDownload{Sha1: obj.Hash, Size: obj.Size})
+			if _, err := os.Stat(objPath); err != nil {
+				return fmt.Errorf("%w: asset %s", util.ErrOffline, name)
+			}
+		}
+		return nil
+	}
+
+	// The first failure cancels the remaining downloads
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
 	openConns := make(chan struct{}, 150)
 	for i := 0; i < 150; i++ {
 		openConns <- struct{}{}
 	}
 
-	objectsPath := path.Join(i.assetsDir, "objects")
-
 	wg := sync.WaitGroup{}
-	wg.Add(len(index.Objects))
 	for _, obj := range index.Objects {
+		// Read from connection pool, giving up if the install has been cancelled
+		select {
+		case <-openConns:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
 		go func(obj *gameModel.AssetObject) {
 			defer wg.Done()
-
-			// Read from connection pool and then at the end write back to it
-			<-openConns
 			defer func() {
 				openConns <- struct{}{}
 			}()
@@ -167,13 +316,39 @@ func (i *Installer) downloadAssetObjects(totalSize int64, index *gameModel.Asset
 			objUrl := fmt.Sprintf("%s/%s/%s", gameModel.MojangObjectBaseUrl, obj.Hash[:2], obj.Hash)
 
 			dl := util.FileDownload{Sha1: obj.Hash, Size: obj.Size, Url: objUrl}
-			if err := util.Download("", objPath, dl); err != nil {
-				panic(err) //todo handle this case better
+			if i.linkFromCache(objPath, dl) {
+				return
+			}
+			if err := util.Download(ctx, i.client, objPath, dl); err != nil {
+				cancel(fmt.Errorf("failed to download asset %s: %w", obj.Hash, err))
 			}
 		}(obj)
 	}
 
 	wg.Wait()
-	return nil
+	return context.Cause(ctx)
+}
+
+// Download fetches the given file if it does not exist yet, preferring a matching file from the cache roots.
+// In offline mode a missing file is an error.
+func (i *Installer) Download(ctx context.Context, file string, dl util.FileDownload) error {
+	i.linkFromCache(file, dl)
+	if i.offline {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("%w: %s", util.ErrOffline, path.Base(file))
+		}
+		return nil
+	}
+	return util.Download(ctx, i.client, file, dl)
+}
 
+// readOrDownload is the same as Download, but also decodes the json content of the file into ptr.
+func (i *Installer) readOrDownload(ctx context.Context, file string, dl util.FileDownload, ptr interface{}) error {
+	i.linkFromCache(file, dl)
+	if i.offline {
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("%w: %s", util.ErrOffline, path.Base(file))
+		}
+	}
+	return util.ReadOrDownload(ctx, i.client, file, dl, ptr)
 }
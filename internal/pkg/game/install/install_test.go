@@ -0,0 +1,192 @@
+package install
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  gameModel.VersionSpec
+		valid bool
+	}{
+		{"standalone", gameModel.VersionSpec{Id: "custom", MainClass: "net.minecraft.client.main.Main"}, true},
+		{"inherited", gameModel.VersionSpec{Id: "custom", InheritsFrom: "1.20.4"}, true},
+		{"missing id", gameModel.VersionSpec{MainClass: "Main"}, false},
+		{"path id", gameModel.VersionSpec{Id: "../custom", MainClass: "Main"}, false},
+		{"nested id", gameModel.VersionSpec{Id: "a/b", MainClass: "Main"}, false},
+		{"self inherit", gameModel.VersionSpec{Id: "custom", InheritsFrom: "custom"}, false},
+		{"missing main class", gameModel.VersionSpec{Id: "custom"}, false},
+		{"unnamed library", gameModel.VersionSpec{Id: "custom", InheritsFrom: "1.20.4", Libraries: []*gameModel.Library{{}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSpec(&tt.spec)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidSpec)
+			}
+		})
+	}
+}
+
+func TestLinkAssets(t *testing.T) {
+	assetsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(path.Join(assetsDir, "objects", "aa"), 0755))
+	require.NoError(t, os.WriteFile(path.Join(assetsDir, "objects", "aa", "aa11"), []byte("sound"), 0644))
+
+	index := &gameModel.AssetIndex{
+		Virtual: true,
+		Objects: map[string]*gameModel.AssetObject{
+			"sounds/random/click.ogg": {Hash: "aa11", Size: 5},
+		},
+	}
+	dest := path.Join(assetsDir, "virtual", "legacy")
+	require.NoError(t, LinkAssets(assetsDir, index, dest))
+
+	content, err := os.ReadFile(path.Join(dest, "sounds", "random", "click.ogg"))
+	require.NoError(t, err)
+	assert.Equal(t, "sound", string(content))
+
+	// Relinking an existing tree is a no-op
+	require.NoError(t, LinkAssets(assetsDir, index, dest))
+
+	// Names escaping the destination are rejected
+	index.Objects = map[string]*gameModel.AssetObject{"../escape": {Hash: "aa11", Size: 5}}
+	assert.Error(t, LinkAssets(assetsDir, index, dest))
+}
+
+func TestPlan(t *testing.T) {
+	dataDir := t.TempDir()
+	writeFile := func(file, content string) {
+		require.NoError(t, os.MkdirAll(path.Dir(path.Join(dataDir, file)), 0755))
+		require.NoError(t, os.WriteFile(path.Join(dataDir, file), []byte(content), 0644))
+	}
+	writeFile("versions/child/child.json", `{"id": "child", "inheritsFrom": "parent",
+		"libraries": [{"name": "a:b:1", "downloads": {"artifact": {"path": "a/b/1/b-1.jar", "size": 5}}}]}`)
+	writeFile("versions/parent/parent.json", `{"id": "parent", "mainClass": "Main",
+		"downloads": {"client": {"size": 10}},
+		"libraries": [{"name": "a:b:1", "downloads": {"artifact": {"path": "a/b/1/b-1.jar", "size": 5}}}],
+		"assetIndex": {"id": "1", "size": 2, "totalSize": 100}}`)
+	writeFile("libraries/a/b/1/b-1.jar", "lib")
+
+	// Offline so that the missing asset index is planned using its total size
+	installer := NewInstaller(dataDir, http.DefaultClient, true, func(_ context.Context, id string) (*gameModel.VersionInfo, error) {
+		return &gameModel.VersionInfo{Id: id}, nil
+	})
+	plan, err := installer.Plan(context.Background(), &gameModel.VersionInfo{Id: "child"})
+	require.NoError(t, err)
+
+	var kinds []FileKind
+	for _, file := range plan.Files {
+		kinds = append(kinds, file.Kind)
+	}
+	assert.Equal(t, []FileKind{FileVersionSpec, FileVersionSpec, FileClient, FileLibrary, FileAssetIndex, FileAssetObject}, kinds)
+	assert.Equal(t, int64(10+2+100), plan.DownloadSize)
+}
+
+func TestInstallLegacy(t *testing.T) {
+	sound := []byte("sound")
+	soundHash := fmt.Sprintf("%x", sha1.Sum(sound))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/legacy.json":
+			_, _ = fmt.Fprintf(w, `{"virtual": true, "objects": {"sounds/random/click.ogg": {"hash": "%s", "size": 5}}}`, soundHash)
+		default:
+			_, _ = w.Write([]byte(r.URL.Path))
+		}
+	}))
+	defer server.Close()
+
+	// Objects are always downloaded from Mojang, so the object is already present
+	dataDir := t.TempDir()
+	objectPath := path.Join(dataDir, "assets", "objects", soundHash[:2], soundHash)
+	require.NoError(t, os.MkdirAll(path.Dir(objectPath), 0755))
+	require.NoError(t, os.WriteFile(objectPath, sound, 0644))
+
+	var spec gameModel.VersionSpec
+	require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{
+		"id": "1.6.4",
+		"minimumLauncherVersion": 13,
+		"mainClass": "net.minecraft.client.main.Main",
+		"minecraftArguments": "--assetsDir ${game_assets}",
+		"downloads": {"client": {"url": "%[1]s/client.jar"}},
+		"assetIndex": {"id": "legacy", "url": "%[1]s/legacy.json"},
+		"libraries": [{
+			"name": "org.lwjgl.lwjgl:lwjgl-platform:2.9.0",
+			"natives": {"linux": "natives-linux", "osx": "natives-osx", "windows": "natives-windows"},
+			"downloads": {"classifiers": {
+				"natives-linux": {"path": "lwjgl-platform-natives.jar", "url": "%[1]s/natives-linux.jar"},
+				"natives-osx": {"path": "lwjgl-platform-natives.jar", "url": "%[1]s/natives-osx.jar"},
+				"natives-windows": {"path": "lwjgl-platform-natives.jar", "url": "%[1]s/natives-windows.jar"}
+			}}
+		}]
+	}`, server.URL)), &spec))
+
+	installer := NewInstaller(dataDir, server.Client(), false, nil)
+	require.NoError(t, installer.InstallFromSpec(context.Background(), &spec))
+
+	assert.FileExists(t, path.Join(dataDir, "versions", "1.6.4", "1.6.4.jar"))
+	assert.FileExists(t, path.Join(dataDir, "libraries", "lwjgl-platform-natives.jar"))
+	content, err := os.ReadFile(path.Join(dataDir, "assets", "virtual", "legacy", "sounds", "random", "click.ogg"))
+	require.NoError(t, err)
+	assert.Equal(t, "sound", string(content))
+
+	spec.MinimumLauncherVersion = MaxLauncherVersion + 1
+	assert.ErrorIs(t, installer.InstallFromSpec(context.Background(), &spec), ErrUnsupportedLauncherVersion)
+}
+
+func TestInstallFromJson(t *testing.T) {
+	dataDir := t.TempDir()
+	sourceDir := t.TempDir()
+	writeSpec := func(name, mainClass string) string {
+		file := path.Join(sourceDir, name)
+		spec := fmt.Sprintf(`{"id": "custom", "mainClass": "%s"}`, mainClass)
+		require.NoError(t, os.WriteFile(file, []byte(spec), 0644))
+		return file
+	}
+	specPath := path.Join(dataDir, "versions", "custom", "custom.json")
+
+	installer := NewInstaller(dataDir, nil, true, nil)
+	installer.SetManifestVersions(func(id string) bool { return id == "1.20.4" })
+
+	id, err := installer.InstallFromJson(context.Background(), writeSpec("custom.json", "a.Main"), false)
+	require.NoError(t, err)
+	assert.Equal(t, "custom", id)
+
+	// The same spec may be installed again, a different one only when forced
+	_, err = installer.InstallFromJson(context.Background(), writeSpec("same.json", "a.Main"), false)
+	assert.NoError(t, err)
+	_, err = installer.InstallFromJson(context.Background(), writeSpec("other.json", "b.Main"), false)
+	assert.ErrorIs(t, err, ErrVersionExists)
+	data, err := os.ReadFile(specPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "a.Main")
+
+	_, err = installer.InstallFromJson(context.Background(), writeSpec("other.json", "b.Main"), true)
+	require.NoError(t, err)
+	data, err = os.ReadFile(specPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "b.Main")
+
+	// Versions from the manifest are never replaced
+	vanilla := path.Join(sourceDir, "vanilla.json")
+	require.NoError(t, os.WriteFile(vanilla, []byte(`{"id": "1.20.4", "mainClass": "a.Main"}`), 0644))
+	_, err = installer.InstallFromJson(context.Background(), vanilla, true)
+	assert.ErrorIs(t, err, ErrVersionExists)
+	assert.NoDirExists(t, path.Join(dataDir, "versions", "1.20.4"))
+}
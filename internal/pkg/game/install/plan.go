@@ -0,0 +1,176 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/game/rule"
+	"github.com/mworzala/mc/internal/pkg/platform"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+type FileKind string
+
+const (
+	FileVersionSpec    FileKind = "version_spec"
+	FileVersionArchive FileKind = "version_archive"
+	FileClient         FileKind = "client"
+	FileLibrary        FileKind = "library"
+	FileAssetIndex     FileKind = "asset_index"
+	FileAssetObject    FileKind = "asset_object"
+	FileLogConfig      FileKind = "log_config"
+)
+
+// PlannedFile is a single file required to install a version.
+type PlannedFile struct {
+	Kind    FileKind
+	Path    string
+	Url     string
+	Size    int64 // Zero if unknown
+	Present bool
+	// Cached is set if the file is missing but will be linked from a cache root rather than downloaded
+	Cached bool
+}
+
+// Plan is the list of files required to install a version, see Installer.Plan.
+type Plan struct {
+	Files []*PlannedFile
+	// DownloadSize is the total size of every file which is not present or cached. Files of unknown size are
+	// not counted.
+	DownloadSize int64
+
+	paths      map[string]bool
+	findCached func(string, util.FileDownload) string
+}
+
+func (p *Plan) add(kind FileKind, file string, dl util.FileDownload) {
+	if p.paths[file] {
+		return
+	}
+	p.paths[file] = true
+
+	_, err := os.Stat(file)
+	entry := &PlannedFile{Kind: kind, Path: file, Url: dl.Url, Size: dl.Size, Present: err == nil}
+	if !entry.Present {
+		entry.Cached = p.findCached(file, dl) != ""
+	}
+	p.Files = append(p.Files, entry)
+	if !entry.Present && !entry.Cached {
+		p.DownloadSize += entry.Size
+	}
+}
+
+// Plan resolves the spec chain of the given version and returns every file Install would need, without
+// writing anything. Version specs and asset indexes which are not present are fetched into memory, because
+// they are required to resolve the remaining files.
+//
+// Versions distributed as an archive are planned as only the archive until it has been extracted, because the
+// spec inside it is unknown.
+//
+// In offline mode a missing asset index is planned using its total size, however a missing version spec is
+// still an error because the chain cannot be resolved.
+func (i *Installer) Plan(ctx context.Context, v *gameModel.VersionInfo) (*Plan, error) {
+	plan := &Plan{paths: make(map[string]bool), findCached: i.findCached}
+	if err := i.planVersion(ctx, plan, v); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (i *Installer) planVersion(ctx context.Context, plan *Plan, v *gameModel.VersionInfo) error {
+	var spec gameModel.VersionSpec
+	versionSpecPath := path.Join(i.versionsDir, v.Id, fmt.Sprintf("%s.json", v.Id))
+	if plan.paths[versionSpecPath] {
+		return nil
+	}
+	if _, err := os.Stat(versionSpecPath); err != nil && isVersionArchive(v) {
+		archivePath := path.Join(i.versionsDir, v.Id, fmt.Sprintf("%s.zip", v.Id))
+		plan.add(FileVersionArchive, archivePath, util.FileDownload{Url: v.Url, Sha1: v.Sha1})
+		return nil
+	}
+	if err := i.readOrFetch(ctx, versionSpecPath, util.FileDownload{Url: v.Url}, &spec); err != nil {
+		return fmt.Errorf("failed to read version spec: %w", err)
+	}
+	plan.add(FileVersionSpec, versionSpecPath, util.FileDownload{Url: v.Url})
+
+	// The inherited version is installed first
+	if spec.InheritsFrom != "" {
+		inherited, err := i.getVersionFunc(ctx, spec.InheritsFrom)
+		if errors.Is(err, util.ErrOffline) {
+			return err
+		} else if err != nil {
+			return fmt.Errorf("inherited version not found: %s", spec.InheritsFrom)
+		}
+
+		if err := i.planVersion(ctx, plan, inherited); err != nil {
+			return err
+		}
+	}
+
+	if spec.Downloads != nil && spec.Downloads.Client != nil {
+		clientPath := path.Join(i.versionsDir, spec.Id, fmt.Sprintf("%s.jar", spec.Id))
+		plan.add(FileClient, clientPath, *spec.Downloads.Client)
+	}
+
+	for _, library := range spec.Libraries {
+		if i.rules.Eval(library.Rules) == rule.Deny {
+			continue
+		}
+
+		if library.Downloads != nil {
+			for _, artifact := range []*gameModel.LibraryArtifact{&library.Downloads.Artifact, library.NativeArtifact(platform.Name, rule.HostArch())} {
+				if artifact != nil && artifact.Path != "" {
+					plan.add(FileLibrary, path.Join(i.librariesDir, artifact.Path), artifact.FileDownload)
+				}
+			}
+		} else if library.Url != "" {
+			artifactPath := library.ArtifactPath()
+			artifactUrl := fmt.Sprintf("%s/%s", strings.TrimSuffix(library.Url, "/"), artifactPath)
+			plan.add(FileLibrary, path.Join(i.librariesDir, artifactPath), util.FileDownload{Url: artifactUrl})
+		}
+	}
+
+	if index := spec.AssetIndex; index != nil {
+		var assetIndex gameModel.AssetIndex
+		assetIndexPath := path.Join(i.assetsDir, "indexes", fmt.Sprintf("%s.json", index.Id))
+		err := i.readOrFetch(ctx, assetIndexPath, index.FileDownload, &assetIndex)
+		plan.add(FileAssetIndex, assetIndexPath, index.FileDownload)
+
+		if errors.Is(err, util.ErrOffline) {
+			// The objects are unknown without the index, so plan them as a whole
+			plan.Files = append(plan.Files, &PlannedFile{Kind: FileAssetObject, Path: path.Join(i.assetsDir, "objects"), Size: index.TotalSize})
+			plan.DownloadSize += index.TotalSize
+		} else if err != nil {
+			return fmt.Errorf("failed to read asset index: %w", err)
+		} else {
+			for _, obj := range assetIndex.Objects {
+				objPath := path.Join(i.assetsDir, "objects", obj.Hash[:2], obj.Hash)
+				objUrl := fmt.Sprintf("%s/%s/%s", gameModel.MojangObjectBaseUrl, obj.Hash[:2], obj.Hash)
+				plan.add(FileAssetObject, objPath, util.FileDownload{Sha1: obj.Hash, Size: obj.Size, Url: objUrl})
+			}
+		}
+	}
+
+	if logging := spec.Logging; logging != nil {
+		logConfigPath := path.Join(i.assetsDir, "log_configs", logging.Client.File.Id)
+		plan.add(FileLogConfig, logConfigPath, logging.Client.File.FileDownload)
+	}
+
+	return nil
+}
+
+// readOrFetch reads the given json file if it exists, otherwise it is fetched into memory without being written.
+func (i *Installer) readOrFetch(ctx context.Context, file string, dl util.FileDownload, ptr interface{}) error {
+	if _, err := os.Stat(file); err == nil {
+		return util.ReadFile(file, ptr)
+	}
+	if i.offline {
+		return fmt.Errorf("%w: %s", util.ErrOffline, path.Base(file))
+	}
+	return util.FetchJson(ctx, i.client, dl.Url, ptr)
+}
@@ -1,6 +1,7 @@
 package launch
 
 import (
+	"archive/zip"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +16,8 @@ import (
 	"github.com/mworzala/mc/internal/pkg/game/rule"
 
 	"github.com/mworzala/mc/internal/pkg/account"
+	"github.com/mworzala/mc/internal/pkg/game"
+	"github.com/mworzala/mc/internal/pkg/game/install"
 	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
 	"github.com/mworzala/mc/internal/pkg/java"
 	"github.com/mworzala/mc/internal/pkg/profile"
@@ -30,38 +33,43 @@ func LaunchProfile(
 	javaInstall *java.Installation,
 	tail bool,
 	quickPlay *QuickPlay,
+	gameManager game.Manager,
 ) error {
-	var spec gameModel.VersionSpec
-
-	versionSpecPath := path.Join(dataDir, "versions", p.Version, fmt.Sprintf("%s.json", p.Version))
-	if err := util.ReadFile(versionSpecPath, &spec); err != nil {
+	spec, err := ReadSpec(dataDir, p.Version)
+	if err != nil {
 		return err
 	}
-	if spec.InheritsFrom != "" {
 
-		//todo should move away from merging specs, it creates weird edge cases like the one below to choose the client jar
-		var inheritedSpec gameModel.VersionSpec
-		inheritedVersionSpecPath := path.Join(dataDir, "versions", spec.InheritsFrom, fmt.Sprintf("%s.json", spec.InheritsFrom))
-		if err := util.ReadFile(inheritedVersionSpecPath, &inheritedSpec); err != nil {
-			return err
-		}
+	gameAssets, err := legacyAssetsDir(dataDir, p.Directory, spec)
+	if err != nil {
+		// The game still starts without the named assets, it is only missing sounds and translations
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %s\n", err)
+		gameAssets = path.Join(dataDir, "assets")
+	}
 
-		spec = *mergeSpec(&spec, &inheritedSpec)
+	nativesDir, err := extractNatives(dataDir, p.Version, spec)
+	if err != nil {
+		return err
 	}
 
 	vars := map[string]string{
 		// jvm
-		"natives_directory": ".",
-		"launcher_name":     "mc",
-		"launcher_version":  "0.0.1",
+		"natives_directory":   nativesDir,
+		"library_directory":   path.Join(dataDir, "libraries"),
+		"classpath_separator": platform.ClasspathSeparator,
+		"launcher_name":       "mc",
+		"launcher_version":    "0.0.1",
 		// game
 		"version_name":      p.Version,
 		"game_directory":    p.Directory,
 		"assets_root":       path.Join(dataDir, "assets"),
 		"assets_index_name": spec.Assets,
+		"game_assets":       gameAssets,
 		"auth_player_name":  acc.Profile.Username,
 		"auth_uuid":         util.TrimUUID(acc.UUID),
 		"auth_access_token": accessToken,
+		"auth_session":      accessToken,
+		"user_properties":   "{}",
 		// Clientid seems to be the mso client id, without dashes, base64 encoded. Should try it with my own client id to see if that works
 		"clientid":          "MTMwQUU2ODYwQUE1NDUwNkIyNUZCMzZBNjFCNjc3M0Q=",
 		"user_type":         "msa",
@@ -96,19 +104,10 @@ func LaunchProfile(
 			continue
 		}
 
-		if lib.Downloads != nil { // Vanilla-type library
-			libPath := path.Join(librariesPath, lib.Downloads.Artifact.Path)
-			classpath.WriteString(libPath)
-		} else if lib.Url != "" { // Direct maven library
-			parts := strings.Split(lib.Name, ":")
-			groupId := parts[0]
-			artifactName := parts[1]
-			version := parts[2]
-
-			artifactPath := fmt.Sprintf("%s/%s/%s/%s-%s.jar", strings.ReplaceAll(groupId, ".", "/"), artifactName, version, artifactName, version)
+		if artifactPath := lib.ArtifactPath(); artifactPath != "" && (lib.Downloads != nil || lib.Url != "") {
 			classpath.WriteString(path.Join(librariesPath, artifactPath))
+			classpath.WriteString(platform.ClasspathSeparator)
 		}
-		classpath.WriteString(platform.ClasspathSeparator)
 	}
 
 	if spec.InheritsFrom != "" {
@@ -123,6 +122,136 @@ func LaunchProfile(
 		vars["auth_xuid"] = msoTokenData.UserHash
 	}
 
+	args := buildArgs(spec, rules, vars, p.Config())
+
+	cmd := exec.Command(javaInstall.Path, args...)
+	cmd.Dir = p.Directory
+
+	if tail {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stdout
+	} else {
+		cmd.Stdout = io.Discard
+	}
+
+	if err := cmd.Start(); err != nil {
+		panic(err)
+	}
+
+	// Record the process so that the profile is known to be in use
+	gameManager.AddProcess(p.Name, cmd.Process.Pid)
+	if err := gameManager.Save(); err != nil {
+		return err
+	}
+
+	if tail {
+		if err := cmd.Wait(); err != nil {
+			panic(err)
+		}
+	}
+
+	return nil
+}
+
+// legacyAssetsDir returns the directory which legacy versions read assets from by name, relinking the named
+// asset tree in case it was removed or is incomplete. Versions using the object store use the assets root.
+func legacyAssetsDir(dataDir, gameDir string, spec *gameModel.VersionSpec) (string, error) {
+	assetsDir := path.Join(dataDir, "assets")
+	if spec.AssetIndex == nil {
+		return assetsDir, nil
+	}
+
+	var assetIndex gameModel.AssetIndex
+	assetIndexPath := path.Join(assetsDir, "indexes", fmt.Sprintf("%s.json", spec.AssetIndex.Id))
+	if err := util.ReadFile(assetIndexPath, &assetIndex); err != nil {
+		return "", fmt.Errorf("failed to read asset index %s: %w", spec.AssetIndex.Id, err)
+	}
+
+	gameAssets := assetsDir
+	if assetIndex.MapToResources {
+		gameAssets = path.Join(gameDir, "resources")
+	} else if assetIndex.Virtual {
+		gameAssets = path.Join(assetsDir, "virtual", spec.AssetIndex.Id)
+	} else {
+		return assetsDir, nil
+	}
+	// Existing files are skipped
+	if err := install.LinkAssets(assetsDir, &assetIndex, gameAssets); err != nil {
+		return "", err
+	}
+	return gameAssets, nil
+}
+
+// extractNatives extracts the native libraries of versions which do not load them from the classpath into the
+// version directory, returning the directory. Versions without such natives use the working directory.
+func extractNatives(dataDir, version string, spec *gameModel.VersionSpec) (string, error) {
+	rules := rule.NewEvaluator()
+	nativesDir := path.Join(dataDir, "versions", version, "natives")
+	found := false
+	for _, lib := range spec.Libraries {
+		natives := lib.NativeArtifact(platform.Name, rule.HostArch())
+		if natives == nil || rules.Eval(lib.Rules) == rule.Deny {
+			continue
+		}
+		found = true
+
+		var exclude []string
+		if lib.Extract != nil {
+			exclude = lib.Extract.Exclude
+		}
+		if err := extractNativesJar(path.Join(dataDir, "libraries", natives.Path), nativesDir, exclude); err != nil {
+			return "", fmt.Errorf("failed to extract natives of %s: %w", lib.Name, err)
+		}
+	}
+	if !found {
+		return ".", nil
+	}
+	return nativesDir, nil
+}
+
+// extractNativesJar extracts every file in the jar which is not below one of the excluded prefixes (eg
+// META-INF/). Existing files are kept, they may be in use by a running instance of the same version.
+func extractNativesJar(file, dest string, exclude []string) error {
+	r, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, entry := range r.File {
+		excluded := entry.FileInfo().IsDir()
+		for _, prefix := range exclude {
+			excluded = excluded || strings.HasPrefix(entry.Name, prefix)
+		}
+		if excluded {
+			continue
+		}
+		target, err := util.SafeJoin(dest, entry.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		data, err := util.ReadZipFile(&r.Reader, entry.Name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(path.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Jvm args of versions before 1.13, which are not included in their spec
+var legacyJvmArgs = []string{"-Djava.library.path=${natives_directory}", "-cp", "${classpath}"}
+
+// buildArgs returns the arguments passed to java to launch the spec, with vars replaced.
+func buildArgs(spec *gameModel.VersionSpec, rules *rule.Evaluator, vars map[string]string, config *profile.Config) []string {
 	replaceVars := func(s string) string {
 		for k, v := range vars {
 			s = strings.ReplaceAll(s, fmt.Sprintf("${%s}", k), v)
@@ -130,64 +259,45 @@ func LaunchProfile(
 		return s
 	}
 
-	var args []string
+	// Versions before 1.13 have their game args in a single string, and no jvm args
+	legacy := len(spec.Arguments.Game) == 0 && spec.MinecraftArguments != ""
 
-	for _, arg := range spec.Arguments.JVM {
-		if s, ok := arg.(string); ok {
-			args = append(args, replaceVars(s))
-		} else if m, ok := arg.(map[string]interface{}); ok {
-			var ruleDef []*rule.Rule
+	var args []string
+	if legacy && len(spec.Arguments.JVM) == 0 {
+		for _, arg := range legacyJvmArgs {
+			args = append(args, replaceVars(arg))
+		}
+	} else {
+		args = appendSpecArgs(args, spec.Arguments.JVM, rules, replaceVars)
+	}
 
-			md, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
-				DecodeHook: func(from, to reflect.Type, value interface{}) (interface{}, error) {
-					if from.Kind() == reflect.String && to == reflect.TypeOf(rule.Allow) {
-						if value.(string) == "allow" {
-							return rule.Allow, nil
-						}
-						return rule.Deny, nil
-					}
-					return value, nil
-				},
-				Result: &ruleDef,
-			})
-			if err != nil {
-				panic("todo")
-			}
-			if err := md.Decode(m["rules"]); err != nil {
-				panic(fmt.Errorf("invalid rule: %w", err)) //todo better error handling. Should print about this and add an option to ignore unknown rules
-			}
+	// Profile jvm args come last so that they override those of the version
+	if config.MinMemory > 0 {
+		args = append(args, fmt.Sprintf("-Xms%dM", config.MinMemory))
+	}
+	if config.MaxMemory > 0 {
+		args = append(args, fmt.Sprintf("-Xmx%dM", config.MaxMemory))
+	}
+	args = append(args, config.JvmArgs...)
 
-			if rules.Eval(ruleDef) == rule.Deny {
-				continue
-			}
+	args = append(args, spec.MainClass)
 
-			// Add the rules
-			switch value := m["value"].(type) {
-			case string:
-				args = append(args, replaceVars(value))
-			case []interface{}:
-				for _, v := range value {
-					if s, ok := v.(string); ok {
-						args = append(args, replaceVars(s))
-					} else {
-						panic(fmt.Sprintf("unknown inner value type: %T", v))
-					}
-				}
-			default:
-				panic(fmt.Sprintf("unknown value type: %T", value))
-			}
-		} else {
-			panic("unknown arg type")
+	if legacy {
+		for _, arg := range strings.Fields(spec.MinecraftArguments) {
+			args = append(args, replaceVars(arg))
 		}
+	} else {
+		args = appendSpecArgs(args, spec.Arguments.Game, rules, replaceVars)
 	}
+	return args
+}
 
-	args = append(args, spec.MainClass)
-
-	for _, arg := range spec.Arguments.Game {
+// appendSpecArgs appends the arguments from a spec which are allowed by the rules.
+func appendSpecArgs(args []string, specArgs []interface{}, rules *rule.Evaluator, replaceVars func(string) string) []string {
+	for _, arg := range specArgs {
 		if s, ok := arg.(string); ok {
 			args = append(args, replaceVars(s))
 		} else if m, ok := arg.(map[string]interface{}); ok {
-			//todo duplicated above
 			var ruleDef []*rule.Rule
 
 			md, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -232,28 +342,29 @@ func LaunchProfile(
 			panic("unknown arg type")
 		}
 	}
+	return args
+}
 
-	cmd := exec.Command(javaInstall.Path, args...)
-	cmd.Dir = p.Directory
-
-	if tail {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stdout
-	} else {
-		cmd.Stdout = io.Discard
-	}
+// ReadSpec reads the installed spec of the given version, merged with the spec it inherits from.
+func ReadSpec(dataDir, version string) (*gameModel.VersionSpec, error) {
+	var spec gameModel.VersionSpec
 
-	if err := cmd.Start(); err != nil {
-		panic(err)
+	versionSpecPath := path.Join(dataDir, "versions", version, fmt.Sprintf("%s.json", version))
+	if err := util.ReadFile(versionSpecPath, &spec); err != nil {
+		return nil, err
 	}
+	if spec.InheritsFrom != "" {
 
-	if tail {
-		if err := cmd.Wait(); err != nil {
-			panic(err)
+		//todo should move away from merging specs, it creates weird edge cases like the one below to choose the client jar
+		var inheritedSpec gameModel.VersionSpec
+		inheritedVersionSpecPath := path.Join(dataDir, "versions", spec.InheritsFrom, fmt.Sprintf("%s.json", spec.InheritsFrom))
+		if err := util.ReadFile(inheritedVersionSpecPath, &inheritedSpec); err != nil {
+			return nil, err
 		}
-	}
 
-	return nil
+		return mergeSpec(&spec, &inheritedSpec), nil
+	}
+	return &spec, nil
 }
 
 func mergeSpec(spec, base *gameModel.VersionSpec) *gameModel.VersionSpec {
@@ -321,6 +432,12 @@ func mergeSpec(spec, base *gameModel.VersionSpec) *gameModel.VersionSpec {
 		result.Assets = base.Assets
 	}
 
+	if spec.MinecraftArguments != "" {
+		result.MinecraftArguments = spec.MinecraftArguments
+	} else {
+		result.MinecraftArguments = base.MinecraftArguments
+	}
+
 	result.Arguments.JVM = append(spec.Arguments.JVM, base.Arguments.JVM...)
 	result.Arguments.Game = append(spec.Arguments.Game, base.Arguments.Game...)
 
@@ -0,0 +1,105 @@
+package launch
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/game/rule"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildArgsLegacy(t *testing.T) {
+	var spec gameModel.VersionSpec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "1.7.10",
+		"mainClass": "net.minecraft.client.main.Main",
+		"minecraftArguments": "--username ${auth_player_name} --assetsDir ${game_assets} --userProperties ${user_properties}"
+	}`), &spec))
+
+	vars := map[string]string{
+		"natives_directory": ".",
+		"classpath":         "a.jar:b.jar",
+		"auth_player_name":  "notch",
+		"game_assets":       "/data/assets/virtual/legacy",
+		"user_properties":   "{}",
+	}
+	args := buildArgs(&spec, rule.NewEvaluator(), vars, &profile.Config{MaxMemory: 2048})
+	assert.Equal(t, []string{
+		"-Djava.library.path=.", "-cp", "a.jar:b.jar", "-Xmx2048M",
+		"net.minecraft.client.main.Main",
+		"--username", "notch", "--assetsDir", "/data/assets/virtual/legacy", "--userProperties", "{}",
+	}, args)
+}
+
+func TestBuildArgsModern(t *testing.T) {
+	var spec gameModel.VersionSpec
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "1.20.4",
+		"mainClass": "net.minecraft.client.main.Main",
+		"arguments": {
+			"game": ["--username", "${auth_player_name}", {"rules": [{"action": "allow", "features": {"is_demo_user": true}}], "value": "--demo"}],
+			"jvm": ["-cp", "${classpath}"]
+		}
+	}`), &spec))
+
+	vars := map[string]string{"classpath": "a.jar", "auth_player_name": "notch"}
+	args := buildArgs(&spec, rule.NewEvaluator(), vars, &profile.Config{})
+	assert.Equal(t, []string{"-cp", "a.jar", "net.minecraft.client.main.Main", "--username", "notch"}, args)
+}
+
+func TestLegacyAssetsDirMissingIndex(t *testing.T) {
+	dataDir := t.TempDir()
+	var spec gameModel.VersionSpec
+	require.NoError(t, json.Unmarshal([]byte(`{"assetIndex": {"id": "legacy"}}`), &spec))
+
+	_, err := legacyAssetsDir(dataDir, t.TempDir(), &spec)
+	assert.Error(t, err)
+
+	require.NoError(t, os.MkdirAll(path.Join(dataDir, "assets", "indexes"), 0755))
+	require.NoError(t, os.WriteFile(path.Join(dataDir, "assets", "indexes", "legacy.json"), []byte(`{"virtual": true, "objects": {}}`), 0644))
+	dir, err := legacyAssetsDir(dataDir, t.TempDir(), &spec)
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(dataDir, "assets", "virtual", "legacy"), dir)
+}
+
+func TestExtractNatives(t *testing.T) {
+	dataDir := t.TempDir()
+	jarPath := path.Join(dataDir, "libraries", "lwjgl-platform-natives.jar")
+	require.NoError(t, os.MkdirAll(path.Dir(jarPath), 0755))
+	f, err := os.Create(jarPath)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"META-INF/MANIFEST.MF", "liblwjgl.so"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(name))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	var spec gameModel.VersionSpec
+	require.NoError(t, json.Unmarshal([]byte(`{"libraries": [{
+		"name": "org.lwjgl.lwjgl:lwjgl-platform:2.9.0",
+		"natives": {"linux": "natives", "osx": "natives", "windows": "natives"},
+		"extract": {"exclude": ["META-INF/"]},
+		"downloads": {"classifiers": {"natives": {"path": "lwjgl-platform-natives.jar"}}}
+	}]}`), &spec))
+
+	dir, err := extractNatives(dataDir, "1.7.10", &spec)
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(dataDir, "versions", "1.7.10", "natives"), dir)
+	assert.FileExists(t, path.Join(dir, "liblwjgl.so"))
+	assert.NoDirExists(t, path.Join(dir, "META-INF"))
+
+	// Versions which load natives from the classpath use the working directory
+	dir, err = extractNatives(dataDir, "1.20.4", &gameModel.VersionSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, ".", dir)
+}
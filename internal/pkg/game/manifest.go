@@ -0,0 +1,373 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const manifestSourcesDir = "manifests"
+
+const (
+	SourceUpdated     = "updated"
+	SourceNotModified = "not modified"
+	SourceFailed      = "failed"
+)
+
+// SourceStatus is the result of refreshing a single upstream manifest.
+type SourceStatus struct {
+	Name   string
+	Status string
+	Err    error
+}
+
+type manifestSource struct {
+	name string
+	url  string
+}
+
+// sourceMeta is stored next to the cached body of a source to send conditional requests.
+type sourceMeta struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// manifestSection is the part of VersionManifestV2 built from one or more sources. If a source has
+// never been fetched successfully, the section is kept from the previous manifest instead.
+type manifestSection struct {
+	sources []manifestSource
+	parse   func(result *VersionManifestV2, data [][]byte) error
+	keep    func(result, previous *VersionManifestV2)
+}
+
+func defaultManifestSections() []manifestSection {
+	return []manifestSection{
+		{
+			sources: []manifestSource{{"mojang", versionManifestUrl}},
+			parse:   parseVanillaManifest,
+			keep: func(result, previous *VersionManifestV2) {
+				result.Vanilla.Release = previous.Vanilla.Release
+				result.Vanilla.Snapshot = previous.Vanilla.Snapshot
+				keepVanillaVersions(result, previous, false)
+			},
+		},
+		// Experimental versions are hosted by Fabric, so they are a separate section which cannot hold back the others
+		{
+			sources: []manifestSource{{"experimental", experimentalVersionManifestUrl}},
+			parse:   parseExperimentalManifest,
+			keep: func(result, previous *VersionManifestV2) {
+				keepVanillaVersions(result, previous, true)
+			},
+		},
+		{
+			sources: []manifestSource{{"fabric-loader", fabricLoaderManifestUrl}, {"fabric-game", fabricVersionManifestUrl}},
+			parse:   fabricLoader.parseManifest,
+			keep: func(result, previous *VersionManifestV2) {
+				result.Fabric = previous.Fabric
+			},
+		},
+		{
+			sources: []manifestSource{{"quilt-loader", quiltLoaderManifestUrl}, {"quilt-game", quiltVersionManifestUrl}},
+			parse:   quiltLoader.parseManifest,
+			keep: func(result, previous *VersionManifestV2) {
+				result.Quilt = previous.Quilt
+			},
+		},
+		{
+			sources: []manifestSource{{"forge-metadata", forgeMavenMetadataUrl}, {"forge-promotions", forgePromotionsUrl}},
+			parse:   parseForgeManifest,
+			keep: func(result, previous *VersionManifestV2) {
+				result.Forge = previous.Forge
+			},
+		},
+		{
+			sources: []manifestSource{{"neoforge-metadata", neoForgeMavenMetadataUrl}},
+			parse:   parseNeoForgeManifest,
+			keep: func(result, previous *VersionManifestV2) {
+				result.NeoForge = previous.NeoForge
+			},
+		},
+	}
+}
+
+// Refresh fetches every upstream manifest which has changed since it was last fetched, and rebuilds the
+// version manifest. A source which fails does not prevent the others from updating, the last successfully
+// fetched copy of it is used instead.
+func (m *VersionManager) Refresh(ctx context.Context) []*SourceStatus {
+	result := newVersionManifestV2()
+	result.LastUpdated = time.Now()
+	result.FormatVersion = versionManifestFormat
+
+	previous := m.manifestV2
+	if previous == nil {
+		previous = newVersionManifestV2()
+	}
+
+	var statuses []*SourceStatus
+	for _, section := range m.sections {
+		data := make([][]byte, len(section.sources))
+		sectionStatuses := make([]*SourceStatus, len(section.sources))
+		complete := true
+		for i, source := range section.sources {
+			body, status, err := m.fetchSource(ctx, source)
+			sectionStatuses[i] = &SourceStatus{Name: source.name, Status: status, Err: err}
+			data[i] = body
+			complete = complete && body != nil
+		}
+		statuses = append(statuses, sectionStatuses...)
+
+		// Without a copy of every source the section is kept from the previous manifest
+		if !complete {
+			section.keep(result, previous)
+			continue
+		}
+		if err := section.parse(result, data); err != nil {
+			for _, status := range sectionStatuses {
+				status.Status, status.Err = SourceFailed, err
+			}
+			section.keep(result, previous)
+		}
+	}
+
+	m.manifestV2 = result
+	// Nothing can be installed without the vanilla versions (the experimental ones alone are not enough), so the
+	// manifest is neither stamped nor saved and the next lookup tries again rather than waiting for the ttl to expire
+	if result.Vanilla.Release == "" {
+		result.LastUpdated = previous.LastUpdated
+		return statuses
+	}
+	if err := m.writeManifest(); err != nil {
+		statuses = append(statuses, &SourceStatus{Name: versionManifestV2File, Status: SourceFailed, Err: err})
+	}
+	return statuses
+}
+
+func (m *VersionManager) updateManifest(ctx context.Context) error {
+	if m.offline {
+		return util.ErrOffline
+	}
+
+	var errs []error
+	for _, status := range m.Refresh(ctx) {
+		if status.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to update %s manifest: %w", status.Name, status.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *VersionManager) writeManifest() error {
+	f, err := os.OpenFile(m.cacheFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(m.manifestV2)
+}
+
+// fetchSource returns the body of the given source, using a conditional request if it has been fetched
+// before. If the request fails, the cached body (if any) is returned along with the error.
+func (m *VersionManager) fetchSource(ctx context.Context, source manifestSource) ([]byte, string, error) {
+	bodyPath := path.Join(m.sourcesDir, source.name)
+	metaPath := bodyPath + ".meta.json"
+
+	var meta sourceMeta
+	cached, err := os.ReadFile(bodyPath)
+	if err != nil {
+		cached = nil
+	} else {
+		_ = util.ReadFile(metaPath, &meta)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.url, nil)
+	if err != nil {
+		return cached, SourceFailed, err
+	}
+	if cached != nil && meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if cached != nil && meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+
+	res, err := m.client.Do(req)
+	if err != nil {
+		return cached, SourceFailed, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && cached != nil:
+		return cached, SourceNotModified, nil
+	case res.StatusCode != http.StatusOK:
+		return cached, SourceFailed, fmt.Errorf("unexpected status: %s", res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return cached, SourceFailed, err
+	}
+
+	// Failing to write the cache only costs a full request next time
+	meta = sourceMeta{ETag: res.Header.Get("ETag"), LastModified: res.Header.Get("Last-Modified")}
+	if err := os.MkdirAll(m.sourcesDir, 0755); err == nil {
+		if err := os.WriteFile(bodyPath, body, 0644); err == nil {
+			if metaData, err := json.Marshal(meta); err == nil {
+				_ = os.WriteFile(metaPath, metaData, 0644)
+			}
+		}
+	}
+
+	return body, SourceUpdated, nil
+}
+
+func parseVanillaManifest(result *VersionManifestV2, data [][]byte) error {
+	return addVanillaVersions(result, data[0], false)
+}
+
+func parseExperimentalManifest(result *VersionManifestV2, data [][]byte) error {
+	return addVanillaVersions(result, data[0], true)
+}
+
+// addVanillaVersions adds the versions from a Mojang style manifest to the vanilla versions.
+func addVanillaVersions(result *VersionManifestV2, data []byte, experimental bool) error {
+	var manifest mojangVersionManifestV2
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+
+	if manifest.Latest != nil && !experimental {
+		result.Vanilla.Release = manifest.Latest.Release
+		result.Vanilla.Snapshot = manifest.Latest.Snapshot
+	}
+	// Experimental versions are listed with their own types (eg pending), and may be distributed as an archive
+	for _, v := range manifest.Versions {
+		result.Vanilla.Versions[v.Id] = &gameModel.VersionInfo{
+			Id:           v.Id,
+			Stable:       v.Type == gameModel.VersionTypeRelease && !experimental,
+			Url:          v.Url,
+			Sha1:         v.Sha1,
+			Type:         v.Type,
+			Experimental: experimental,
+			ReleaseTime:  v.ReleaseTime,
+		}
+	}
+	return nil
+}
+
+// keepVanillaVersions copies the (non-)experimental vanilla versions of the previous manifest.
+func keepVanillaVersions(result, previous *VersionManifestV2, experimental bool) {
+	for id, v := range previous.Vanilla.Versions {
+		if v.Experimental == experimental {
+			result.Vanilla.Versions[id] = v
+		}
+	}
+}
+
+// parseManifest reads the loader and game version manifests of a loader using the Fabric meta API.
+func (ml *metaLoader) parseManifest(result *VersionManifestV2, data [][]byte) error {
+	versions := ml.versions(result)
+
+	var loaders fabricLoaderManifestV2
+	if err := json.Unmarshal(data[0], &loaders); err != nil {
+		return err
+	}
+	for _, v := range loaders {
+		// Without a stable flag, anything with a pre-release suffix (eg 0.20.0-beta.1) is unstable
+		stable := !strings.Contains(v.Version, "-")
+		if v.Stable != nil {
+			stable = *v.Stable
+		}
+		if stable && versions.DefaultLoader == "" {
+			versions.DefaultLoader = v.Version
+		}
+		versions.Loaders[v.Version] = stable
+	}
+
+	var gameVersions fabricVersionManifestV2
+	if err := json.Unmarshal(data[1], &gameVersions); err != nil {
+		return err
+	}
+	for _, v := range gameVersions {
+		versions.Versions[v.Version] = &gameModel.VersionInfo{
+			Id:     fmt.Sprintf("%s-loader-%%s-%s", ml.name, v.Version),
+			Stable: v.Stable,
+			Url:    fmt.Sprintf("%s/%s/%%s/profile/json", ml.specBaseUrl, v.Version),
+		}
+	}
+	return nil
+}
+
+func parseForgeManifest(result *VersionManifestV2, data [][]byte) error {
+	var metadata mavenMetadata
+	if err := xml.Unmarshal(data[0], &metadata); err != nil {
+		return err
+	}
+	for _, mavenVersion := range metadata.Versioning.Versions {
+		// Maven versions are `<minecraft>-<forge>`, old ones may also have a `-<minecraft>` suffix
+		gameVersion, loader, ok := strings.Cut(mavenVersion, "-")
+		if !ok {
+			continue
+		}
+		loader = strings.TrimSuffix(loader, "-"+gameVersion)
+
+		if result.Forge.Versions[gameVersion] == nil {
+			result.Forge.Versions[gameVersion] = make(map[string]string)
+		}
+		result.Forge.Versions[gameVersion][loader] = mavenVersion
+	}
+
+	var promotions forgePromotions
+	if err := json.Unmarshal(data[1], &promotions); err != nil {
+		return err
+	}
+	for promo, loader := range promotions.Promos {
+		if gameVersion, ok := strings.CutSuffix(promo, "-recommended"); ok {
+			result.Forge.Recommended[gameVersion] = loader
+		} else if gameVersion, ok := strings.CutSuffix(promo, "-latest"); ok {
+			// Only use latest if there is no recommended version
+			if _, ok := promotions.Promos[gameVersion+"-recommended"]; !ok {
+				result.Forge.Recommended[gameVersion] = loader
+			}
+		}
+	}
+	return nil
+}
+
+func parseNeoForgeManifest(result *VersionManifestV2, data [][]byte) error {
+	var metadata mavenMetadata
+	if err := xml.Unmarshal(data[0], &metadata); err != nil {
+		return err
+	}
+
+	// Versions are listed oldest first, so the last stable version seen is the latest
+	for _, loader := range metadata.Versioning.Versions {
+		gameVersion, ok := neoForgeGameVersion(loader)
+		if !ok {
+			continue
+		}
+
+		stable := !strings.Contains(loader, "-")
+		if result.NeoForge.Versions[gameVersion] == nil {
+			result.NeoForge.Versions[gameVersion] = make(map[string]bool)
+		}
+		result.NeoForge.Versions[gameVersion][loader] = stable
+
+		if latest, ok := result.NeoForge.Latest[gameVersion]; stable || !ok || strings.Contains(latest, "-") {
+			result.NeoForge.Latest[gameVersion] = loader
+		}
+	}
+	return nil
+}
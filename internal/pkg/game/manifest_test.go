@@ -0,0 +1,161 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh(t *testing.T) {
+	fabricUp := true
+	var mojangRequests, mojangNotModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mojang":
+			mojangRequests++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				mojangNotModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"latest":{"release":"1.21","snapshot":"1.21"},"versions":[{"id":"1.21","type":"release","url":"https://example.com/1.21.json"}]}`))
+		case "/experimental":
+			_, _ = w.Write([]byte(`{"versions":[]}`))
+		case "/fabric-loader":
+			if !fabricUp {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"version":"0.16.0","stable":true}]`))
+		case "/fabric-game":
+			_, _ = w.Write([]byte(`[{"version":"1.21","stable":true}]`))
+		}
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	m := &VersionManager{
+		client:     server.Client(),
+		cacheFile:  path.Join(dataDir, versionManifestV2File),
+		sourcesDir: path.Join(dataDir, manifestSourcesDir),
+		sections:   defaultManifestSections()[:3],
+	}
+	m.sections[0].sources = []manifestSource{{"mojang", server.URL + "/mojang"}}
+	m.sections[1].sources = []manifestSource{{"experimental", server.URL + "/experimental"}}
+	m.sections[2].sources = []manifestSource{{"fabric-loader", server.URL + "/fabric-loader"}, {"fabric-game", server.URL + "/fabric-game"}}
+
+	// First refresh downloads everything
+	require.NoError(t, m.updateManifest(context.Background()))
+	assert.Contains(t, m.manifestV2.Vanilla.Versions, "1.21")
+	assert.Equal(t, "0.16.0", m.DefaultFabricLoader())
+
+	// Second refresh uses the etag, and the fabric failure keeps the cached copy
+	fabricUp = false
+	statuses := m.Refresh(context.Background())
+	assert.Equal(t, 2, mojangRequests)
+	assert.Equal(t, 1, mojangNotModified)
+
+	byName := make(map[string]*SourceStatus)
+	for _, status := range statuses {
+		byName[status.Name] = status
+	}
+	assert.Equal(t, SourceNotModified, byName["mojang"].Status)
+	assert.Equal(t, SourceFailed, byName["fabric-loader"].Status)
+	assert.Error(t, byName["fabric-loader"].Err)
+	assert.Equal(t, SourceUpdated, byName["fabric-game"].Status)
+
+	assert.Contains(t, m.manifestV2.Vanilla.Versions, "1.21")
+	assert.Equal(t, "0.16.0", m.DefaultFabricLoader())
+	assert.True(t, m.FabricSupported("1.21"))
+}
+
+func TestRefreshWithoutVanilla(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	m := &VersionManager{
+		client:     server.Client(),
+		cacheFile:  path.Join(dataDir, versionManifestV2File),
+		sourcesDir: path.Join(dataDir, manifestSourcesDir),
+		sections:   defaultManifestSections()[:2],
+	}
+	m.sections[0].sources = []manifestSource{{"mojang", server.URL + "/mojang"}}
+	m.sections[1].sources = []manifestSource{{"experimental", server.URL + "/experimental"}}
+
+	// A failed first refresh is not saved, and is retried on the next lookup
+	assert.Error(t, m.updateManifest(context.Background()))
+	assert.True(t, m.manifestV2.LastUpdated.IsZero())
+	assert.NoFileExists(t, m.cacheFile)
+}
+
+func TestRefreshWithoutExperimental(t *testing.T) {
+	experimentalUp := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mojang":
+			_, _ = w.Write([]byte(`{"latest":{"release":"1.21","snapshot":"1.21"},"versions":[{"id":"1.21","type":"release","url":"https://example.com/1.21.json"}]}`))
+		case "/experimental":
+			if !experimentalUp {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"versions":[{"id":"1.21-exp","type":"pending","url":"https://example.com/1.21-exp.zip"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	m := &VersionManager{
+		client:     server.Client(),
+		cacheFile:  path.Join(dataDir, versionManifestV2File),
+		sourcesDir: path.Join(dataDir, manifestSourcesDir),
+		sections:   defaultManifestSections()[:2],
+	}
+	m.sections[0].sources = []manifestSource{{"mojang", server.URL + "/mojang"}}
+	m.sections[1].sources = []manifestSource{{"experimental", server.URL + "/experimental"}}
+
+	// The vanilla versions are usable and saved on a fresh install
+	assert.Error(t, m.updateManifest(context.Background()))
+	assert.Contains(t, m.manifestV2.Vanilla.Versions, "1.21")
+	assert.Equal(t, "1.21", m.manifestV2.Vanilla.Release)
+	assert.FileExists(t, m.cacheFile)
+
+	experimentalUp = true
+	require.NoError(t, m.updateManifest(context.Background()))
+	assert.True(t, m.manifestV2.Vanilla.Versions["1.21-exp"].Experimental)
+
+	// Cached experimental versions are kept when the source fails again
+	experimentalUp = false
+	require.NoError(t, os.RemoveAll(m.sourcesDir))
+	assert.Error(t, m.updateManifest(context.Background()))
+	assert.Contains(t, m.manifestV2.Vanilla.Versions, "1.21")
+	assert.Contains(t, m.manifestV2.Vanilla.Versions, "1.21-exp")
+}
+
+func TestParseMetaLoaderManifest(t *testing.T) {
+	result := newVersionManifestV2()
+	gameVersions := []byte(`[{"version":"1.21","stable":true}]`)
+
+	fabricLoaders := []byte(`[{"version":"0.16.1","stable":false},{"version":"0.16.0","stable":true}]`)
+	require.NoError(t, fabricLoader.parseManifest(result, [][]byte{fabricLoaders, gameVersions}))
+	assert.Equal(t, "0.16.0", result.Fabric.DefaultLoader)
+	assert.Equal(t, "fabric-loader-%s-1.21", result.Fabric.Versions["1.21"].Id)
+
+	// Quilt loaders are not marked stable
+	quiltLoaders := []byte(`[{"version":"0.26.0-beta.1"},{"version":"0.25.0"}]`)
+	require.NoError(t, quiltLoader.parseManifest(result, [][]byte{quiltLoaders, gameVersions}))
+	assert.Equal(t, "0.25.0", result.Quilt.DefaultLoader)
+	assert.False(t, result.Quilt.Loaders["0.26.0-beta.1"])
+	assert.Equal(t, "quilt-loader-%s-1.21", result.Quilt.Versions["1.21"].Id)
+	assert.Equal(t, "0.16.0", result.Fabric.DefaultLoader)
+}
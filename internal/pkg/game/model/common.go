@@ -1,7 +1,50 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	VersionTypeRelease  = "release"
+	VersionTypeSnapshot = "snapshot"
+	VersionTypeOldBeta  = "old_beta"
+	VersionTypeOldAlpha = "old_alpha"
+)
+
 type VersionInfo struct {
 	Id     string
 	Stable bool
 	Url    string
+	// Sha1 of the file at Url, if known
+	Sha1 string
+
+	// Type, Experimental and ReleaseTime are only known for vanilla versions. Experimental versions keep the
+	// type from their manifest, eg pending.
+	Type         string
+	Experimental bool
+	ReleaseTime  time.Time
+}
+
+// MavenPath converts a maven coordinate (eg `net.fabricmc:access-widener:2.1.0`) into the relative
+// path of the artifact inside a maven repository. An optional classifier and extension are supported,
+// eg `net.minecraft:client:1.20.1:mappings@txt`.
+func MavenPath(coordinate string) string {
+	ext := "jar"
+	if i := strings.LastIndex(coordinate, "@"); i != -1 {
+		coordinate, ext = coordinate[:i], coordinate[i+1:]
+	}
+
+	parts := strings.Split(coordinate, ":")
+	if len(parts) < 3 {
+		return ""
+	}
+	groupId, artifactName, version := parts[0], parts[1], parts[2]
+
+	fileName := fmt.Sprintf("%s-%s", artifactName, version)
+	if len(parts) > 3 && parts[3] != "" {
+		fileName = fmt.Sprintf("%s-%s", fileName, parts[3])
+	}
+	return fmt.Sprintf("%s/%s/%s/%s.%s", strings.ReplaceAll(groupId, ".", "/"), artifactName, version, fileName, ext)
 }
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMavenPath(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"simple", "net.fabricmc:access-widener:2.1.0", "net/fabricmc/access-widener/2.1.0/access-widener-2.1.0.jar"},
+		{"classifier", "net.minecraftforge:forge:1.20.1-47.2.0:universal", "net/minecraftforge/forge/1.20.1-47.2.0/forge-1.20.1-47.2.0-universal.jar"},
+		{"extension", "net.minecraft:client:1.20.1-20230612.114412:mappings@txt", "net/minecraft/client/1.20.1-20230612.114412/client-1.20.1-20230612.114412-mappings.txt"},
+		{"extension no classifier", "de.oceanlabs.mcp:mcp_config:1.20.1@zip", "de/oceanlabs/mcp/mcp_config/1.20.1/mcp_config-1.20.1.zip"},
+		{"invalid", "not-a-coordinate", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.out, MavenPath(test.in))
+		})
+	}
+}
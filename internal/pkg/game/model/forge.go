@@ -0,0 +1,57 @@
+package model
+
+import "strings"
+
+const InstallProfileFileName = "install_profile.json"
+
+// InstallProfile is the install_profile.json found in Forge style installer jars (spec 0 and 1).
+//
+// The installer contains a version spec (at Json) inheriting from the vanilla version, as well as a list
+// of processors which must be run after installing the libraries to patch the vanilla client.
+type InstallProfile struct {
+	Spec      int    `json:"spec"`
+	Version   string `json:"version"`
+	Json      string `json:"json"`
+	Minecraft string `json:"minecraft"`
+
+	// Data contains the values which can be referenced in processor arguments as `{KEY}`.
+	// A value is either a maven artifact `[group:artifact:version]`, a literal `'value'`
+	// or a path to a file inside the installer jar.
+	Data       map[string]SidedValue `json:"data"`
+	Processors []*Processor          `json:"processors"`
+	Libraries  []*Library            `json:"libraries"`
+}
+
+type SidedValue struct {
+	Client string `json:"client"`
+	Server string `json:"server"`
+}
+
+type Processor struct {
+	// Sides the processor should run on, or empty for all sides
+	Sides     []string          `json:"sides"`
+	Jar       string            `json:"jar"`
+	Classpath []string          `json:"classpath"`
+	Args      []string          `json:"args"`
+	Outputs   map[string]string `json:"outputs"`
+}
+
+// ArtifactReferences returns the maven coordinate of every artifact referenced in the client data,
+// these are the files created by processors.
+func (p *InstallProfile) ArtifactReferences() (result []string) {
+	for _, value := range p.Data {
+		if coordinate, ok := ParseArtifactReference(value.Client); ok {
+			result = append(result, coordinate)
+		}
+	}
+	return
+}
+
+// ParseArtifactReference returns the maven coordinate of an install profile value in the form
+// `[group:artifact:version]`, or false if the value is not an artifact reference.
+func ParseArtifactReference(value string) (string, bool) {
+	if len(value) > 2 && strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
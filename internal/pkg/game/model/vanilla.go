@@ -1,6 +1,8 @@
 package model
 
 import (
+	"strings"
+
 	"github.com/mworzala/mc/internal/pkg/game/rule"
 	"github.com/mworzala/mc/internal/pkg/util"
 )
@@ -55,6 +57,8 @@ type VersionSpec struct {
 		Game []interface{} `json:"game"`
 		JVM  []interface{} `json:"jvm"`
 	} `json:"arguments"`
+	// MinecraftArguments are the space separated game args of versions before 1.13, which have no Arguments
+	MinecraftArguments string `json:"minecraftArguments"`
 }
 
 // Library is blah blah blah
@@ -70,21 +74,58 @@ type Library struct {
 
 	// Vanilla
 	Downloads *struct {
-		Artifact struct {
-			Path string `json:"path"`
-			util.FileDownload
-		} `json:"artifact"`
+		Artifact    LibraryArtifact             `json:"artifact"`
+		Classifiers map[string]*LibraryArtifact `json:"classifiers"`
 	} `json:"downloads"`
+	// Natives maps an os name to the classifier of the native library for it, eg natives-windows-${arch}.
+	// Only used by versions which extract natives at launch (before 1.19), the natives are not on the classpath.
+	Natives map[string]string `json:"natives"`
+	Extract *struct {
+		Exclude []string `json:"exclude"`
+	} `json:"extract"`
 
 	// Direct maven
 	Url string `json:"url"`
 }
 
+type LibraryArtifact struct {
+	Path string `json:"path"`
+	util.FileDownload
+}
+
 type AssetIndex struct {
-	Objects map[string]*AssetObject `json:"objects"`
+	// Virtual indicates that the game reads assets by name from a virtual directory (assets/virtual/<index>)
+	// instead of the object store. Used by the legacy index (1.6 to 1.7.2).
+	Virtual bool `json:"virtual"`
+	// MapToResources indicates that the game reads assets by name from the resources directory in the game
+	// directory. Used by the pre-1.6 index.
+	MapToResources bool                    `json:"map_to_resources"`
+	Objects        map[string]*AssetObject `json:"objects"`
 }
 
 type AssetObject struct {
 	Hash string `json:"hash"`
 	Size int64  `json:"size"`
 }
+
+// ArtifactPath returns the path of the library jar relative to the libraries directory.
+func (l *Library) ArtifactPath() string {
+	if l.Downloads != nil {
+		return l.Downloads.Artifact.Path
+	}
+	return MavenPath(l.Name)
+}
+
+// NativeArtifact returns the native library artifact for the given os and arch (as named in rules), or nil
+// if the library has no natives for it.
+func (l *Library) NativeArtifact(osName, arch string) *LibraryArtifact {
+	classifier := l.Natives[osName]
+	if classifier == "" || l.Downloads == nil {
+		return nil
+	}
+	bits := "64"
+	if arch == "x86" {
+		bits = "32"
+	}
+	return l.Downloads.Classifiers[strings.ReplaceAll(classifier, "${arch}", bits)]
+}
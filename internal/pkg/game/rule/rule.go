@@ -63,7 +63,7 @@ func NewEvaluator(features ...string) *Evaluator {
 
 	return &Evaluator{
 		os:       platform.Name,
-		arch:     determineArch(),
+		arch:     HostArch(),
 		version:  determineVersion(),
 		features: featureMap,
 	}
@@ -114,7 +114,8 @@ func (e *Evaluator) evalSingle(rule *Rule) Action {
 	return action
 }
 
-func determineArch() string {
+// HostArch returns the architecture of the host as named in rules, eg x86_64 or arm64.
+func HostArch() string {
 	switch runtime.GOARCH {
 	case "amd64":
 		return "x86_64"
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+// Kind is the category of a shared game file in the data directory.
+type Kind string
+
+const (
+	Version     Kind = "version"
+	Library     Kind = "library"
+	AssetIndex  Kind = "asset_index"
+	AssetObject Kind = "asset_object"
+	// VirtualAssets is the named asset tree of a legacy (virtual) asset index
+	VirtualAssets Kind = "virtual_assets"
+	LogConfig     Kind = "log_config"
+)
+
+// References is the set of shared game files which are still used by at least one version.
+type References struct {
+	Versions     map[string]bool
+	Libraries    map[string]bool // Relative to the libraries directory
+	AssetIndexes map[string]bool
+	AssetObjects map[string]bool // By hash
+	LogConfigs   map[string]bool
+}
+
+// Entry is a single file or directory found in the data directory.
+type Entry struct {
+	Kind Kind
+	Path string
+	Size int64
+}
+
+// FindReferences resolves the spec chain of each given version and returns every shared file they use.
+//
+// Versions which are not installed are ignored, however a spec which exists and cannot be read is an
+// error, because anything it references would otherwise be considered garbage.
+func FindReferences(dataDir string, versions []string) (*References, error) {
+	refs := &References{
+		Versions:     make(map[string]bool),
+		Libraries:    make(map[string]bool),
+		AssetIndexes: make(map[string]bool),
+		AssetObjects: make(map[string]bool),
+		LogConfigs:   make(map[string]bool),
+	}
+
+	for _, version := range versions {
+		if err := refs.addVersion(dataDir, version); err != nil {
+			return nil, err
+		}
+	}
+
+	// Asset objects are only known after reading every referenced index
+	for id := range refs.AssetIndexes {
+		var index gameModel.AssetIndex
+		indexPath := path.Join(dataDir, "assets", "indexes", fmt.Sprintf("%s.json", id))
+		if err := util.ReadFile(indexPath, &index); errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read asset index %s: %w", id, err)
+		}
+
+		for _, obj := range index.Objects {
+			refs.AssetObjects[obj.Hash] = true
+		}
+	}
+
+	return refs, nil
+}
+
+func (r *References) addVersion(dataDir, id string) error {
+	if id == "" || r.Versions[id] {
+		return nil
+	}
+
+	var spec gameModel.VersionSpec
+	specPath := path.Join(dataDir, "versions", id, fmt.Sprintf("%s.json", id))
+	if err := util.ReadFile(specPath, &spec); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read version spec %s: %w", id, err)
+	}
+	r.Versions[id] = true
+
+	// Every library is kept regardless of rules, the data directory may be shared between platforms
+	for _, library := range spec.Libraries {
+		if p := library.ArtifactPath(); p != "" {
+			r.Libraries[path.Clean(p)] = true
+		}
+		if library.Downloads != nil {
+			for _, natives := range library.Downloads.Classifiers {
+				r.Libraries[path.Clean(natives.Path)] = true
+			}
+		}
+	}
+	if spec.AssetIndex != nil {
+		r.AssetIndexes[spec.AssetIndex.Id] = true
+	}
+	if spec.Logging != nil {
+		r.LogConfigs[spec.Logging.Client.File.Id] = true
+	}
+
+	// Forge style installs create libraries using processors which are not part of the spec
+	var installProfile gameModel.InstallProfile
+	installProfilePath := path.Join(dataDir, "versions", id, gameModel.InstallProfileFileName)
+	if err := util.ReadFile(installProfilePath, &installProfile); err == nil {
+		for _, library := range installProfile.Libraries {
+			if p := library.ArtifactPath(); p != "" {
+				r.Libraries[path.Clean(p)] = true
+			}
+		}
+		for _, coordinate := range installProfile.ArtifactReferences() {
+			r.Libraries[gameModel.MavenPath(coordinate)] = true
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read install profile %s: %w", id, err)
+	}
+
+	return r.addVersion(dataDir, spec.InheritsFrom)
+}
+
+// FindUnreferenced returns every shared file in the data directory which is not in refs.
+func FindUnreferenced(dataDir string, refs *References) ([]*Entry, error) {
+	var result []*Entry
+
+	// Versions are removed as a whole directory
+	versionsDir := path.Join(dataDir, "versions")
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || refs.Versions[entry.Name()] {
+			continue
+		}
+		versionDir := path.Join(versionsDir, entry.Name())
+		size, err := DirSize(versionDir)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &Entry{Kind: Version, Path: versionDir, Size: size})
+	}
+
+	librariesDir := path.Join(dataDir, "libraries")
+	err = walkFiles(librariesDir, func(rel string, size int64) {
+		if !refs.Libraries[rel] {
+			result = append(result, &Entry{Kind: Library, Path: path.Join(librariesDir, rel), Size: size})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexesDir := path.Join(dataDir, "assets", "indexes")
+	err = walkFiles(indexesDir, func(rel string, size int64) {
+		if !refs.AssetIndexes[strings.TrimSuffix(rel, ".json")] {
+			result = append(result, &Entry{Kind: AssetIndex, Path: path.Join(indexesDir, rel), Size: size})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Virtual asset trees are removed as a whole directory along with their index
+	virtualDir := path.Join(dataDir, "assets", "virtual")
+	entries, err = os.ReadDir(virtualDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || refs.AssetIndexes[entry.Name()] {
+			continue
+		}
+		treeDir := path.Join(virtualDir, entry.Name())
+		size, err := DirSize(treeDir)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &Entry{Kind: VirtualAssets, Path: treeDir, Size: size})
+	}
+
+	objectsDir := path.Join(dataDir, "assets", "objects")
+	err = walkFiles(objectsDir, func(rel string, size int64) {
+		if !refs.AssetObjects[path.Base(rel)] {
+			result = append(result, &Entry{Kind: AssetObject, Path: path.Join(objectsDir, rel), Size: size})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logConfigsDir := path.Join(dataDir, "assets", "log_configs")
+	err = walkFiles(logConfigsDir, func(rel string, size int64) {
+		if !refs.LogConfigs[rel] {
+			result = append(result, &Entry{Kind: LogConfig, Path: path.Join(logConfigsDir, rel), Size: size})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Remove deletes the given entries, and then any directories left empty in the
+// libraries and asset object directories.
+func Remove(dataDir string, entries []*Entry) error {
+	for _, entry := range entries {
+		if err := os.RemoveAll(entry.Path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", entry.Path, err)
+		}
+	}
+
+	for _, dir := range []string{path.Join(dataDir, "libraries"), path.Join(dataDir, "assets", "objects")} {
+		if err := removeEmptyDirs(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DirSize returns the total size of all regular files in the given directory, or zero if it does not exist.
+func DirSize(dir string) (size int64, err error) {
+	err = walkFiles(dir, func(_ string, fileSize int64) {
+		size += fileSize
+	})
+	return
+}
+
+// walkFiles calls fn with the slash separated relative path and size of every regular file in dir.
+// A missing directory is treated as empty.
+func walkFiles(dir string, fn func(rel string, size int64)) error {
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		fn(filepath.ToSlash(rel), info.Size())
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// removeEmptyDirs removes every empty directory below (but not including) root.
+func removeEmptyDirs(root string) error {
+	var dirs []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != root {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Deepest first so that parents are empty by the time they are visited
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			_ = os.Remove(dirs[i])
+		}
+	}
+	return nil
+}
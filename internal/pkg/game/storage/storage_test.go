@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, file, content string) {
+	require.NoError(t, os.MkdirAll(path.Dir(file), 0755))
+	require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+}
+
+func TestCollect(t *testing.T) {
+	dataDir := t.TempDir()
+
+	writeFile(t, path.Join(dataDir, "versions/fabric/fabric.json"), `{
+		"id": "fabric", "inheritsFrom": "1.20.1",
+		"libraries": [{"name": "net.fabricmc:fabric-loader:0.14.21", "url": "https://maven.fabricmc.net/"}]
+	}`)
+	writeFile(t, path.Join(dataDir, "versions/1.20.1/1.20.1.json"), `{
+		"id": "1.20.1",
+		"libraries": [{"name": "a:b:1", "downloads": {"artifact": {"path": "a/b/1/b-1.jar"}}}],
+		"assetIndex": {"id": "5"},
+		"logging": {"client": {"file": {"id": "client-1.12.xml"}}}
+	}`)
+	writeFile(t, path.Join(dataDir, "versions/1.20.1/1.20.1.jar"), "jar")
+	writeFile(t, path.Join(dataDir, "versions/1.19/1.19.json"), `{"id": "1.19", "assetIndex": {"id": "1"}}`)
+	writeFile(t, path.Join(dataDir, "libraries/a/b/1/b-1.jar"), "lib")
+	writeFile(t, path.Join(dataDir, "libraries/net/fabricmc/fabric-loader/0.14.21/fabric-loader-0.14.21.jar"), "lib")
+	writeFile(t, path.Join(dataDir, "libraries/old/lib/1/lib-1.jar"), "old")
+	writeFile(t, path.Join(dataDir, "assets/indexes/5.json"), `{"objects": {"a": {"hash": "aa11", "size": 1}}}`)
+	writeFile(t, path.Join(dataDir, "assets/indexes/1.json"), `{"objects": {"b": {"hash": "bb22", "size": 1}}}`)
+	writeFile(t, path.Join(dataDir, "assets/objects/aa/aa11"), "a")
+	writeFile(t, path.Join(dataDir, "assets/objects/bb/bb22"), "b")
+	writeFile(t, path.Join(dataDir, "assets/log_configs/client-1.12.xml"), "xml")
+	writeFile(t, path.Join(dataDir, "assets/virtual/5/a"), "a")
+	writeFile(t, path.Join(dataDir, "assets/virtual/1/b"), "b")
+
+	refs, err := FindReferences(dataDir, []string{"fabric", "not-installed"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"fabric": true, "1.20.1": true}, refs.Versions)
+
+	unreferenced, err := FindUnreferenced(dataDir, refs)
+	require.NoError(t, err)
+
+	var paths []string
+	for _, entry := range unreferenced {
+		rel, err := filepath.Rel(dataDir, entry.Path)
+		require.NoError(t, err)
+		paths = append(paths, filepath.ToSlash(rel))
+	}
+	require.ElementsMatch(t, []string{
+		"versions/1.19",
+		"libraries/old/lib/1/lib-1.jar",
+		"assets/indexes/1.json",
+		"assets/objects/bb/bb22",
+		"assets/virtual/1",
+	}, paths)
+
+	require.NoError(t, Remove(dataDir, unreferenced))
+	require.NoDirExists(t, path.Join(dataDir, "versions/1.19"))
+	require.NoDirExists(t, path.Join(dataDir, "libraries/old"))
+	require.NoDirExists(t, path.Join(dataDir, "assets/objects/bb"))
+	require.FileExists(t, path.Join(dataDir, "libraries/a/b/1/b-1.jar"))
+	require.FileExists(t, path.Join(dataDir, "assets/objects/aa/aa11"))
+	require.FileExists(t, path.Join(dataDir, "assets/virtual/5/a"))
+}
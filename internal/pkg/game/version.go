@@ -1,6 +1,7 @@
 package game
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,10 +9,14 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mworzala/mc/internal/pkg/config"
 	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/util"
 )
 
 const (
@@ -24,30 +29,64 @@ const (
 	fabricLoaderManifestUrl  = "https://meta.fabricmc.net/v2/versions/loader"
 	fabricVersionSpecBaseUrl = "https://meta.fabricmc.net/v2/versions/loader"
 
+	// Forge
+	forgeMavenMetadataUrl = "https://maven.minecraftforge.net/net/minecraftforge/forge/maven-metadata.xml"
+	forgePromotionsUrl    = "https://files.minecraftforge.net/net/minecraftforge/forge/promotions_slim.json"
+	forgeInstallerBaseUrl = "https://maven.minecraftforge.net/net/minecraftforge/forge"
+
+	// Quilt
+	quiltVersionManifestUrl = "https://meta.quiltmc.org/v3/versions/game"
+	quiltLoaderManifestUrl  = "https://meta.quiltmc.org/v3/versions/loader"
+	quiltVersionSpecBaseUrl = "https://meta.quiltmc.org/v3/versions/loader"
+
+	// NeoForge
+	neoForgeMavenMetadataUrl = "https://maven.neoforged.net/releases/net/neoforged/neoforge/maven-metadata.xml"
+	neoForgeInstallerBaseUrl = "https://maven.neoforged.net/releases/net/neoforged/neoforge"
+
 	versionManifestV2File = "versions_v2.json"
+	defaultManifestTTL    = 10 * time.Minute
+	// versionManifestFormat is incremented when fields are added to VersionManifestV2, so that
+	// older caches are refreshed
+	versionManifestFormat = 3
 )
 
 var (
 	ErrUnknownVersion       = errors.New("unknown version")
 	ErrUnknownFabricVersion = errors.New("unknown fabric version")
 	ErrUnknownFabricLoader  = errors.New("unknown fabric loader")
+	ErrUnknownQuiltVersion  = errors.New("unknown quilt version")
+	ErrUnknownQuiltLoader   = errors.New("unknown quilt loader")
+	ErrUnknownForgeVersion  = errors.New("unknown forge version")
+	ErrUnknownForgeLoader   = errors.New("unknown forge loader")
+
+	ErrUnknownNeoForgeVersion = errors.New("unknown neoforge version")
+	ErrUnknownNeoForgeLoader  = errors.New("unknown neoforge loader")
 )
 
 type (
 	VersionManifestV2 struct {
-		LastUpdated time.Time
-		Vanilla     struct {
+		LastUpdated   time.Time
+		FormatVersion int
+		Vanilla       struct {
 			Release  string
 			Snapshot string
 			// Mapping of Minecraft version to version json url
 			Versions map[string]*gameModel.VersionInfo
 		}
-		Fabric struct {
-			// Versions matrix is a mapping of Minecraft version to fabric support
-			// The entries in here are partial, and should not be used as is
-			Versions      map[string]*gameModel.VersionInfo
-			DefaultLoader string
-			Loaders       map[string]bool
+		Fabric MetaLoaderVersions
+		Quilt  MetaLoaderVersions
+		Forge  struct {
+			// Versions is a mapping of Minecraft version to the forge versions available for it.
+			// Each forge version is mapped to its full maven version, which is used in the installer url.
+			Versions map[string]map[string]string
+			// Mapping of Minecraft version to the recommended forge version (or latest if there is no recommendation)
+			Recommended map[string]string
+		}
+		NeoForge struct {
+			// Mapping of Minecraft version to the neoforge versions available for it
+			Versions map[string]map[string]bool
+			// Mapping of Minecraft version to the latest stable neoforge version (or latest beta if there is none)
+			Latest map[string]string
 		}
 	}
 
@@ -63,37 +102,109 @@ type (
 			Time        time.Time `json:"time"`
 			Type        string    `json:"type"`
 			Url         string    `json:"url"`
+			Sha1        string    `json:"sha1"`
 		} `json:"versions"`
 	}
-	// Response from fabricVersionManifestUrl
+	// MetaLoaderVersions are the versions of a mod loader using the Fabric meta API (Fabric and Quilt)
+	MetaLoaderVersions struct {
+		// Versions matrix is a mapping of Minecraft version to loader support
+		// The entries in here are partial, and should not be used as is
+		Versions      map[string]*gameModel.VersionInfo
+		DefaultLoader string
+		Loaders       map[string]bool
+	}
+
+	// Response from fabricVersionManifestUrl and quiltVersionManifestUrl
 	fabricVersionManifestV2 []struct {
 		Version string `json:"version"`
 		Stable  bool   `json:"stable"`
 	}
-	// Response from fabricLoaderManifestUrl
+	// Response from fabricLoaderManifestUrl and quiltLoaderManifestUrl, quilt does not mark stable loaders
 	fabricLoaderManifestV2 []struct {
 		Separator string `json:"separator"`
 		Build     int    `json:"build"`
 		Maven     string `json:"maven"`
 		Version   string `json:"version"`
-		Stable    bool   `json:"stable"`
+		Stable    *bool  `json:"stable"`
+	}
+	// Response from forgeMavenMetadataUrl
+	mavenMetadata struct {
+		Versioning struct {
+			Latest   string   `xml:"latest"`
+			Release  string   `xml:"release"`
+			Versions []string `xml:"versions>version"`
+		} `xml:"versioning"`
+	}
+	// Response from forgePromotionsUrl
+	forgePromotions struct {
+		Promos map[string]string `json:"promos"`
+	}
+)
+
+// metaLoader is a mod loader using the Fabric meta API.
+type metaLoader struct {
+	name        string // Prefix of the loader version ids, eg fabric for fabric-loader-<loader>-<game version>
+	specBaseUrl string
+
+	errUnknownVersion error
+	errUnknownLoader  error
+	versions          func(manifest *VersionManifestV2) *MetaLoaderVersions
+}
+
+var (
+	fabricLoader = &metaLoader{
+		name:              "fabric",
+		specBaseUrl:       fabricVersionSpecBaseUrl,
+		errUnknownVersion: ErrUnknownFabricVersion,
+		errUnknownLoader:  ErrUnknownFabricLoader,
+		versions:          func(manifest *VersionManifestV2) *MetaLoaderVersions { return &manifest.Fabric },
+	}
+	quiltLoader = &metaLoader{
+		name:              "quilt",
+		specBaseUrl:       quiltVersionSpecBaseUrl,
+		errUnknownVersion: ErrUnknownQuiltVersion,
+		errUnknownLoader:  ErrUnknownQuiltLoader,
+		versions:          func(manifest *VersionManifestV2) *MetaLoaderVersions { return &manifest.Quilt },
 	}
 )
 
 // Version manager
 
 type VersionManager struct {
+	client        *http.Client
 	cacheFile     string
+	sourcesDir    string
+	versionsDir   string
+	offline       bool
+	ttl           time.Duration
+	sections      []manifestSection
 	manifestV2    *VersionManifestV2
 	triedToUpdate bool
 }
 
-func NewVersionManager(dataDir string) (*VersionManager, error) {
+func NewVersionManager(dataDir string, config *config.Config, client *http.Client) (*VersionManager, error) {
 	cacheFile := path.Join(dataDir, versionManifestV2File)
-	m := &VersionManager{cacheFile: cacheFile}
+	m := &VersionManager{
+		client:      client,
+		cacheFile:   cacheFile,
+		sourcesDir:  path.Join(dataDir, manifestSourcesDir),
+		versionsDir: path.Join(dataDir, "versions"),
+		offline:     config.Offline,
+		ttl:         config.ManifestTTL,
+		sections:    defaultManifestSections(),
+	}
+	if m.ttl <= 0 {
+		m.ttl = defaultManifestTTL
+	}
 
 	if _, err := os.Stat(cacheFile); errors.Is(err, fs.ErrNotExist) {
-		if err := m.updateManifest(); err != nil {
+		if m.offline {
+			// Nothing cached yet, only installed versions will be resolvable
+			m.manifestV2 = newVersionManifestV2()
+			return m, nil
+		}
+		// Sources which fail are skipped, but without the vanilla versions there is nothing to use
+		if err := m.updateManifest(context.Background()); err != nil && m.manifestV2.Vanilla.Release == "" {
 			return nil, err
 		}
 	} else {
@@ -108,47 +219,119 @@ func NewVersionManager(dataDir string) (*VersionManager, error) {
 			return nil, fmt.Errorf("failed to read version manifest: %w", err)
 		}
 		m.manifestV2 = &manifest
+
+		// Refresh caches written by an older version, keeping the old cache if it fails
+		if manifest.FormatVersion < versionManifestFormat && !m.offline {
+			_ = m.updateManifest(context.Background())
+		}
 	}
 
 	return m, nil
 }
 
-func (m *VersionManager) LatestVersions() (release, snapshot string, err error) {
-	if m.manifestV2.LastUpdated.Before(time.Now().Add(-10 * time.Minute)) {
+func (m *VersionManager) LatestVersions(ctx context.Context) (release, snapshot string, err error) {
+	m.updateIfStale(ctx)
+
+	return m.manifestV2.Vanilla.Release, m.manifestV2.Vanilla.Snapshot, nil
+}
+
+// updateIfStale updates the manifest if it is older than the configured ttl. Failures are ignored,
+// the previous manifest remains usable.
+func (m *VersionManager) updateIfStale(ctx context.Context) {
+	if !m.offline && m.manifestV2.LastUpdated.Before(time.Now().Add(-m.ttl)) {
 		m.triedToUpdate = true
-		_ = m.updateManifest()
+		_ = m.updateManifest(ctx)
 	}
+}
 
-	return m.manifestV2.Vanilla.Release, m.manifestV2.Vanilla.Snapshot, nil
+// Versions returns every known vanilla version, newest first.
+func (m *VersionManager) Versions(ctx context.Context) []*gameModel.VersionInfo {
+	m.updateIfStale(ctx)
+
+	result := make([]*gameModel.VersionInfo, 0, len(m.manifestV2.Vanilla.Versions))
+	for _, v := range m.manifestV2.Vanilla.Versions {
+		result = append(result, v)
+	}
+	slices.SortFunc(result, func(a, b *gameModel.VersionInfo) int {
+		if c := b.ReleaseTime.Compare(a.ReleaseTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	return result
+}
+
+// updateForMissing updates the manifest once when a version cannot be found in it. A nil error means the lookup
+// should be retried, otherwise notFound is returned, or the context error if the update was cancelled.
+func (m *VersionManager) updateForMissing(ctx context.Context, notFound error) error {
+	if m.triedToUpdate {
+		return notFound
+	}
+	m.triedToUpdate = true
+	// A cancelled update is not an unknown version
+	if err := m.updateManifest(ctx); err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return nil
 }
 
-func (m *VersionManager) FindVanilla(name string) (*gameModel.VersionInfo, error) {
+// FabricSupported returns true if fabric is available for the given Minecraft version.
+func (m *VersionManager) FabricSupported(name string) bool {
+	_, ok := m.manifestV2.Fabric.Versions[strings.ToLower(name)]
+	return ok
+}
+
+func (m *VersionManager) FindVanilla(ctx context.Context, name string) (*gameModel.VersionInfo, error) {
 	v, ok := m.manifestV2.Vanilla.Versions[strings.ToLower(name)]
 	if !ok {
-		if m.triedToUpdate {
-			return nil, ErrUnknownVersion
+		if m.offline {
+			if m.isInstalled(name) {
+				return &gameModel.VersionInfo{Id: name}, nil
+			}
+			return nil, fmt.Errorf("%w: %s", util.ErrOffline, name)
 		}
-		m.triedToUpdate = true
-		_ = m.updateManifest()
-		return m.FindVanilla(name)
+		if err := m.updateForMissing(ctx, ErrUnknownVersion); err != nil {
+			return nil, err
+		}
+		return m.FindVanilla(ctx, name)
 	}
 	m.triedToUpdate = false
 	return v, nil
 }
 
-func (m *VersionManager) FindFabric(name, loader string) (*gameModel.VersionInfo, error) {
-	if !m.FabricLoaderExists(loader) {
-		return nil, ErrUnknownFabricLoader
+func (m *VersionManager) FindFabric(ctx context.Context, name, loader string) (*gameModel.VersionInfo, error) {
+	return m.findMetaLoader(ctx, fabricLoader, name, loader)
+}
+
+func (m *VersionManager) FindQuilt(ctx context.Context, name, loader string) (*gameModel.VersionInfo, error) {
+	return m.findMetaLoader(ctx, quiltLoader, name, loader)
+}
+
+func (m *VersionManager) findMetaLoader(ctx context.Context, ml *metaLoader, name, loader string) (*gameModel.VersionInfo, error) {
+	if m.offline {
+		// The installed spec is enough, the manifest may not know about the loader yet
+		if id := fmt.Sprintf("%s-loader-%s-%s", ml.name, loader, name); m.isInstalled(id) {
+			return &gameModel.VersionInfo{Id: id}, nil
+		}
+	}
+
+	versions := ml.versions(m.manifestV2)
+	if !versions.loaderExists(loader) {
+		if m.offline && len(versions.Loaders) == 0 {
+			return nil, fmt.Errorf("%w: %s loader %s", util.ErrOffline, ml.name, loader)
+		}
+		return nil, ml.errUnknownLoader
 	}
 
-	partial, ok := m.manifestV2.Fabric.Versions[strings.ToLower(name)]
+	partial, ok := versions.Versions[strings.ToLower(name)]
 	if !ok {
-		if m.triedToUpdate {
-			return nil, ErrUnknownFabricVersion
+		if m.offline {
+			return nil, fmt.Errorf("%w: %s %s", util.ErrOffline, ml.name, name)
 		}
-		m.triedToUpdate = true
-		_ = m.updateManifest()
-		return m.FindFabric(name, loader)
+		if err := m.updateForMissing(ctx, ml.errUnknownVersion); err != nil {
+			return nil, err
+		}
+		return m.findMetaLoader(ctx, ml, name, loader)
 	}
 	m.triedToUpdate = false
 
@@ -159,110 +342,176 @@ func (m *VersionManager) FindFabric(name, loader string) (*gameModel.VersionInfo
 	}, nil
 }
 
-func (m *VersionManager) DefaultFabricLoader() string {
-	return m.manifestV2.Fabric.DefaultLoader
-}
+func (m *VersionManager) FindForge(ctx context.Context, name, loader string) (*gameModel.VersionInfo, error) {
+	id := fmt.Sprintf("%s-forge-%s", name, loader)
+	if m.offline && m.isInstalled(id) {
+		return &gameModel.VersionInfo{Id: id}, nil
+	}
 
-func (m *VersionManager) FabricLoaderExists(name string) bool {
-	_, ok := m.manifestV2.Fabric.Loaders[strings.ToLower(name)]
-	return ok
-}
+	loaders, ok := m.manifestV2.Forge.Versions[strings.ToLower(name)]
+	if !ok {
+		if m.offline {
+			return nil, fmt.Errorf("%w: forge %s", util.ErrOffline, name)
+		}
+		if err := m.updateForMissing(ctx, ErrUnknownForgeVersion); err != nil {
+			return nil, err
+		}
+		return m.FindForge(ctx, name, loader)
+	}
+	m.triedToUpdate = false
 
-func (m *VersionManager) updateManifest() error {
-	var result VersionManifestV2
-	result.LastUpdated = time.Now()
-	result.Vanilla.Versions = make(map[string]*gameModel.VersionInfo)
-	result.Fabric.Versions = make(map[string]*gameModel.VersionInfo)
-	result.Fabric.Loaders = make(map[string]bool)
+	mavenVersion, ok := loaders[loader]
+	if !ok {
+		return nil, ErrUnknownForgeLoader
+	}
 
-	updateMojangManifest := func(url string) error {
-		res, err := http.Get(url)
-		if err != nil {
-			return err
-		}
-		defer res.Body.Close()
+	return &gameModel.VersionInfo{
+		Id:     id,
+		Stable: true,
+		Url:    fmt.Sprintf("%s/%s/forge-%s-installer.jar", forgeInstallerBaseUrl, mavenVersion, mavenVersion),
+	}, nil
+}
 
-		var manifest mojangVersionManifestV2
-		if err := json.NewDecoder(res.Body).Decode(&manifest); err != nil {
-			return err
-		}
+func (m *VersionManager) FindNeoForge(ctx context.Context, name, loader string) (*gameModel.VersionInfo, error) {
+	id := fmt.Sprintf("neoforge-%s", loader)
+	if m.offline && m.isInstalled(id) {
+		return &gameModel.VersionInfo{Id: id}, nil
+	}
 
-		if manifest.Latest != nil {
-			result.Vanilla.Release = manifest.Latest.Release
-			result.Vanilla.Snapshot = manifest.Latest.Snapshot
+	loaders, ok := m.manifestV2.NeoForge.Versions[strings.ToLower(name)]
+	if !ok {
+		if m.offline {
+			return nil, fmt.Errorf("%w: neoforge %s", util.ErrOffline, name)
 		}
-		for _, v := range manifest.Versions {
-			result.Vanilla.Versions[v.Id] = &gameModel.VersionInfo{
-				Id:     v.Id,
-				Stable: v.Type == "release",
-				Url:    v.Url,
-			}
+		if err := m.updateForMissing(ctx, ErrUnknownNeoForgeVersion); err != nil {
+			return nil, err
 		}
-
-		return nil
+		return m.FindNeoForge(ctx, name, loader)
 	}
+	m.triedToUpdate = false
 
-	// Pull vanilla and experimental manifests
-	if err := updateMojangManifest(versionManifestUrl); err != nil {
-		return fmt.Errorf("failed to update mojang manifest: %w", err)
-	}
-	if err := updateMojangManifest(experimentalVersionManifestUrl); err != nil {
-		return fmt.Errorf("failed to update experimental manifest: %w", err)
+	stable, ok := loaders[loader]
+	if !ok {
+		return nil, ErrUnknownNeoForgeLoader
 	}
 
-	// Pull fabric loader manifest
-	{
-		res, err := http.Get(fabricLoaderManifestUrl)
-		if err != nil {
-			return err
-		}
-		defer res.Body.Close()
+	return &gameModel.VersionInfo{
+		Id:     id,
+		Stable: stable,
+		Url:    fmt.Sprintf("%s/%s/neoforge-%s-installer.jar", neoForgeInstallerBaseUrl, loader, loader),
+	}, nil
+}
 
-		var manifest fabricLoaderManifestV2
-		if err := json.NewDecoder(res.Body).Decode(&manifest); err != nil {
-			return err
-		}
+// DefaultNeoForgeLoader returns the latest neoforge version for the given Minecraft version,
+// or the empty string if there is none.
+func (m *VersionManager) DefaultNeoForgeLoader(name string) string {
+	return m.manifestV2.NeoForge.Latest[strings.ToLower(name)]
+}
+
+// DefaultForgeLoader returns the recommended forge version for the given Minecraft version,
+// or the empty string if there is none.
+func (m *VersionManager) DefaultForgeLoader(name string) string {
+	return m.manifestV2.Forge.Recommended[strings.ToLower(name)]
+}
+
+func (m *VersionManager) DefaultFabricLoader() string {
+	return m.manifestV2.Fabric.DefaultLoader
+}
+
+func (m *VersionManager) FabricLoaderExists(name string) bool {
+	return m.manifestV2.Fabric.loaderExists(name)
+}
+
+func (m *VersionManager) DefaultQuiltLoader() string {
+	return m.manifestV2.Quilt.DefaultLoader
+}
+
+func (m *VersionManager) QuiltLoaderExists(name string) bool {
+	return m.manifestV2.Quilt.loaderExists(name)
+}
+
+// IsManifestVersion returns true if the id is a vanilla, fabric or quilt version from the manifest.
+func (m *VersionManager) IsManifestVersion(id string) bool {
+	id = strings.ToLower(id)
+	if _, ok := m.manifestV2.Vanilla.Versions[id]; ok {
+		return true
+	}
+	return fabricLoader.isVersion(m.manifestV2, id) || quiltLoader.isVersion(m.manifestV2, id)
+}
 
-		for _, v := range manifest {
-			if v.Stable && result.Fabric.DefaultLoader == "" {
-				result.Fabric.DefaultLoader = v.Version
+// isVersion returns true if the id is named <name>-loader-<loader>-<game version> for a known loader and game version.
+func (ml *metaLoader) isVersion(manifest *VersionManifestV2, id string) bool {
+	rest, ok := strings.CutPrefix(id, ml.name+"-loader-")
+	if !ok {
+		return false
+	}
+	versions := ml.versions(manifest)
+	for loader := range versions.Loaders {
+		if name, ok := strings.CutPrefix(rest, loader+"-"); ok {
+			if _, ok := versions.Versions[name]; ok {
+				return true
 			}
-			result.Fabric.Loaders[v.Version] = v.Stable
 		}
 	}
+	return false
+}
 
-	// Pull fabric versions
-	{
-		res, err := http.Get(fabricVersionManifestUrl)
-		if err != nil {
-			return err
-		}
-		defer res.Body.Close()
+func (v *MetaLoaderVersions) loaderExists(name string) bool {
+	_, ok := v.Loaders[strings.ToLower(name)]
+	return ok
+}
 
-		var manifest fabricVersionManifestV2
-		if err := json.NewDecoder(res.Body).Decode(&manifest); err != nil {
-			return err
-		}
+// isInstalled returns true if the version spec for the given id exists in the versions directory.
+func (m *VersionManager) isInstalled(id string) bool {
+	_, err := os.Stat(path.Join(m.versionsDir, id, fmt.Sprintf("%s.json", id)))
+	return err == nil
+}
 
-		for _, v := range manifest {
-			result.Fabric.Versions[v.Version] = &gameModel.VersionInfo{
-				Id:     fmt.Sprintf("fabric-loader-%%s-%s", v.Version),
-				Stable: v.Stable,
-				Url:    fmt.Sprintf("%s/%s/%%s/profile/json", fabricVersionSpecBaseUrl, v.Version),
-			}
+func newVersionManifestV2() *VersionManifestV2 {
+	var result VersionManifestV2
+	result.Vanilla.Versions = make(map[string]*gameModel.VersionInfo)
+	result.Fabric = newMetaLoaderVersions()
+	result.Quilt = newMetaLoaderVersions()
+	result.Forge.Versions = make(map[string]map[string]string)
+	result.Forge.Recommended = make(map[string]string)
+	result.NeoForge.Versions = make(map[string]map[string]bool)
+	result.NeoForge.Latest = make(map[string]string)
+	return &result
+}
+
+// neoForgeGameVersion returns the Minecraft version of a neoforge version. NeoForge versions are
+// `<minor>.<patch>.<build>` of the Minecraft version, eg 20.4.80-beta is for 1.20.4 and 21.0.1 is for 1.21.
+// For year based Minecraft versions they are `<year>.<drop>.<hotfix>.<build>`, eg 26.1.0.5 is for 26.1.
+func neoForgeGameVersion(loader string) (string, bool) {
+	version, _, _ := strings.Cut(loader, "-")
+	parts := strings.Split(version, ".")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return "", false
 		}
+		numbers[i] = n
 	}
 
-	f, err := os.OpenFile(m.cacheFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
-	if err != nil {
-		return err
+	switch {
+	case len(numbers) == 3 && numbers[0] >= 20 && numbers[0] < 26:
+		if numbers[1] == 0 {
+			return fmt.Sprintf("1.%d", numbers[0]), true
+		}
+		return fmt.Sprintf("1.%d.%d", numbers[0], numbers[1]), true
+	case len(numbers) == 4 && numbers[0] >= 26:
+		if numbers[2] == 0 {
+			return fmt.Sprintf("%d.%d", numbers[0], numbers[1]), true
+		}
+		return fmt.Sprintf("%d.%d.%d", numbers[0], numbers[1], numbers[2]), true
 	}
-	defer f.Close()
+	return "", false
+}
 
-	if err := json.NewEncoder(f).Encode(&result); err != nil {
-		return err
+func newMetaLoaderVersions() MetaLoaderVersions {
+	return MetaLoaderVersions{
+		Versions: make(map[string]*gameModel.VersionInfo),
+		Loaders:  make(map[string]bool),
 	}
-
-	m.manifestV2 = &result
-	return nil
 }
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeoForgeGameVersion(t *testing.T) {
+	tests := []struct {
+		loader   string
+		expected string
+		ok       bool
+	}{
+		{"20.4.80-beta", "1.20.4", true},
+		{"20.2.3-beta", "1.20.2", true},
+		{"21.0.167", "1.21", true},
+		{"21.1.65", "1.21.1", true},
+		{"26.1.0.5", "26.1", true},
+		{"26.1.2.13-beta", "26.1.2", true},
+		{"26.1.5", "", false},
+		{"21.1.0.5", "", false},
+		{"1.20.1-47.1.7", "", false},
+		{"invalid", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.loader, func(t *testing.T) {
+			actual, ok := neoForgeGameVersion(tt.loader)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestIsManifestVersion(t *testing.T) {
+	manifest := newVersionManifestV2()
+	manifest.Vanilla.Versions["1.20.4"] = &gameModel.VersionInfo{Id: "1.20.4"}
+	manifest.Fabric.Versions["1.20.4"] = &gameModel.VersionInfo{}
+	manifest.Fabric.Loaders["0.15.0"] = true
+	manifest.Quilt.Versions["1.20.5-pre1"] = &gameModel.VersionInfo{}
+	manifest.Quilt.Loaders["0.25.0-beta.1"] = true
+	m := &VersionManager{manifestV2: manifest}
+
+	assert.True(t, m.IsManifestVersion("1.20.4"))
+	assert.True(t, m.IsManifestVersion("fabric-loader-0.15.0-1.20.4"))
+	assert.True(t, m.IsManifestVersion("quilt-loader-0.25.0-beta.1-1.20.5-pre1"))
+	assert.False(t, m.IsManifestVersion("fabric-loader-0.15.0-1.20.5-pre1"))
+	assert.False(t, m.IsManifestVersion("fabric-loader-0.14.0-1.20.4"))
+	assert.False(t, m.IsManifestVersion("custom"))
+}
+
+func TestFindMissingVersion(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"latest":{"release":"1.21","snapshot":"1.21"},"versions":[{"id":"1.21","type":"release"}]}`))
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	m := &VersionManager{
+		client:     server.Client(),
+		cacheFile:  path.Join(dataDir, versionManifestV2File),
+		sourcesDir: path.Join(dataDir, manifestSourcesDir),
+		sections:   defaultManifestSections()[:1],
+		manifestV2: newVersionManifestV2(),
+	}
+	m.sections[0].sources = []manifestSource{{"mojang", server.URL}}
+
+	// A cancelled update is reported as such, not as an unknown version
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := m.FindVanilla(ctx, "1.21")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The manifest is updated once for a missing version
+	m.triedToUpdate = false
+	v, err := m.FindVanilla(context.Background(), "1.21")
+	require.NoError(t, err)
+	assert.Equal(t, "1.21", v.Id)
+	_, err = m.FindFabric(context.Background(), "1.21", "")
+	assert.ErrorIs(t, err, ErrUnknownFabricLoader)
+	_, err = m.FindForge(context.Background(), "1.21", "51.0.0")
+	assert.ErrorIs(t, err, ErrUnknownForgeVersion)
+	assert.Equal(t, 2, requests)
+}
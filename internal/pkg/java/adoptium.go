@@ -0,0 +1,161 @@
+package java
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const defaultAdoptiumUrl = "https://api.adoptium.net"
+
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Response from the Adoptium latest assets endpoint
+type adoptiumAssets []struct {
+	Binary struct {
+		Package struct {
+			Name     string `json:"name"`
+			Link     string `json:"link"`
+			Checksum string `json:"checksum"` // sha256
+			Size     int64  `json:"size"`
+		} `json:"package"`
+	} `json:"binary"`
+	ReleaseName string `json:"release_name"`
+}
+
+// installTemurin downloads the latest Eclipse Temurin JDK with the given major version from the Adoptium
+// API and registers it as an installation named `temurin-<major>`. An existing install is replaced.
+func (m *fileManager) installTemurin(ctx context.Context, version string) (*Installation, error) {
+	major, err := strconv.Atoi(version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: temurin@%s", ErrUnknownRuntime, version)
+	}
+	osName, arch := adoptiumPlatform()
+	if osName == "" || arch == "" {
+		return nil, ErrUnsupportedPlatform
+	}
+
+	query := url.Values{}
+	query.Set("architecture", arch)
+	query.Set("image_type", "jdk")
+	query.Set("os", osName)
+	query.Set("vendor", "eclipse")
+	assetsUrl := fmt.Sprintf("%s/v3/assets/latest/%d/hotspot?%s", strings.TrimSuffix(m.AdoptiumUrl, "/"), major, query.Encode())
+
+	var assets adoptiumAssets
+	if err := util.FetchJson(ctx, m.Client, assetsUrl, &assets); err != nil {
+		return nil, fmt.Errorf("failed to query adoptium: %w", err)
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("%w: temurin@%d", ErrUnknownRuntime, major)
+	}
+	pkg := assets[0].Binary.Package
+
+	// Download and verify the archive, it is only needed until extracted
+	name := fmt.Sprintf("temurin-%d", major)
+	archivePath := path.Join(m.RuntimesDir, path.Base(pkg.Name))
+	if err := util.Download(ctx, m.Client, archivePath, util.FileDownload{Url: pkg.Link, Size: pkg.Size}); err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", pkg.Name, err)
+	}
+	defer os.Remove(archivePath)
+	if err := verifySha256(archivePath, pkg.Checksum); err != nil {
+		return nil, err
+	}
+
+	// Extract next to the final location, then replace any previous install
+	installDir := path.Join(m.RuntimesDir, name)
+	tempDir, err := os.MkdirTemp(m.RuntimesDir, name+"-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tempDir)
+	if err := extractArchive(archivePath, tempDir); err != nil {
+		return nil, fmt.Errorf("failed to extract %s: %w", pkg.Name, err)
+	}
+
+	// The archives contain a single top level directory, eg jdk-21.0.3+9
+	root := tempDir
+	if entries, err := os.ReadDir(tempDir); err == nil && len(entries) == 1 && entries[0].IsDir() {
+		root = path.Join(tempDir, entries[0].Name())
+	}
+	if err := os.RemoveAll(installDir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(root, installDir); err != nil {
+		return nil, err
+	}
+
+	install, err := DiscoverJava(path.Join(installDir, javaExecSubPath), name)
+	if err != nil {
+		return nil, err
+	}
+	m.Installs[strings.ToLower(install.Name)] = install
+	return install, nil
+}
+
+// adoptiumPlatform returns the host os and architecture as named by the Adoptium API.
+func adoptiumPlatform() (osName, arch string) {
+	switch runtime.GOOS {
+	case "linux", "windows":
+		osName = runtime.GOOS
+	case "darwin":
+		osName = "mac"
+	}
+	switch runtime.GOARCH {
+	case "amd64":
+		arch = "x64"
+	case "386":
+		arch = "x32"
+	case "arm64":
+		arch = "aarch64"
+	case "arm":
+		arch = "arm"
+	}
+	return
+}
+
+func verifySha256(file, expected string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return err
+	}
+	if actual := fmt.Sprintf("%x", hash.Sum(nil)); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("%w: %s != %s", ErrChecksumMismatch, actual, expected)
+	}
+	return nil
+}
+
+func extractArchive(archivePath, dest string) error {
+	if strings.HasSuffix(archivePath, ".zip") {
+		r, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return util.ExtractZip(&r.Reader, "", dest, false)
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return util.ExtractTarGz(f, dest)
+}
@@ -0,0 +1,75 @@
+package java
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallTemurin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake java executable is a shell script")
+	}
+
+	// A fake jdk whose java prints the properties read by discovery
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	script := "#!/bin/sh\necho 'java.vm.specification.version = 21'\necho 'os.arch = x86_64'\n"
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "jdk-21.0.3+9/", Typeflag: tar.TypeDir, Mode: 0755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: path.Join("jdk-21.0.3+9", javaExecSubPath), Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(script))}))
+	_, err := tw.Write([]byte(script))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	checksum := fmt.Sprintf("%x", sha256.Sum256(archive.Bytes()))
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/assets/latest/21/hotspot":
+			assert.Equal(t, "jdk", r.URL.Query().Get("image_type"))
+			_, _ = fmt.Fprintf(w, `[{"binary": {"package": {"name": "jdk.tar.gz", "link": "%s/jdk.tar.gz", "checksum": "%s"}}}]`, server.URL, checksum)
+		case "/jdk.tar.gz":
+			_, _ = w.Write(archive.Bytes())
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dataDir := t.TempDir()
+	m := &fileManager{
+		Client:      server.Client(),
+		RuntimesDir: path.Join(dataDir, runtimesDirName),
+		AdoptiumUrl: server.URL,
+		Installs:    make(map[string]*Installation),
+	}
+
+	install, err := m.InstallRuntime(context.Background(), "temurin@21")
+	require.NoError(t, err)
+	assert.Equal(t, "temurin-21", install.Name)
+	assert.Equal(t, 21, install.Version)
+	assert.Equal(t, path.Join(m.RuntimesDir, "temurin-21", javaExecSubPath), install.Path)
+	assert.Equal(t, install, m.GetInstallation("temurin-21"))
+
+	// Reinstalling replaces the previous install
+	_, err = m.InstallRuntime(context.Background(), "temurin@21")
+	require.NoError(t, err)
+
+	_, err = m.InstallRuntime(context.Background(), "temurin@8")
+	assert.Error(t, err)
+	_, err = m.InstallRuntime(context.Background(), "unknown@21")
+	assert.ErrorIs(t, err, ErrUnknownRuntime)
+}
@@ -0,0 +1,83 @@
+package java
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrIncompatible = errors.New("incompatible java installation")
+
+// normalizeArch converts the os.arch of an installation to the names used by rules (see rule.HostArch).
+func normalizeArch(arch string) string {
+	switch arch {
+	case "amd64", "x86_64":
+		return "x86_64"
+	case "x86", "i386", "i686":
+		return "x86"
+	case "aarch64", "arm64":
+		return "arm64"
+	}
+	return arch
+}
+
+// CheckCompatibility returns an error wrapping ErrIncompatible if the installation cannot run a version
+// requiring the given major java version (or zero if unknown) on a host with the given architecture. If it
+// can run, but only through emulation or with a risk of failure, a warning is returned instead.
+func CheckCompatibility(install *Installation, hostArch string, requiredMajor int) (warning string, err error) {
+	if install.Broken {
+		return "", fmt.Errorf("%w: %s is broken, run `mc java refresh` to check it again", ErrIncompatible, install.Name)
+	}
+
+	if requiredMajor > 0 && install.Version < requiredMajor {
+		return "", fmt.Errorf("%w: %s is java %d, but java %d or newer is required", ErrIncompatible, install.Name, install.Version, requiredMajor)
+	}
+
+	arch := normalizeArch(install.Arch)
+	switch {
+	case arch == "" || arch == hostArch:
+	case hostArch == "arm64" && (arch == "x86_64" || arch == "x86"):
+		warning = fmt.Sprintf("%s is %s and will run using emulation, which is much slower than a native %s installation", install.Name, arch, hostArch)
+	case hostArch == "x86_64" && arch == "x86":
+		warning = fmt.Sprintf("%s is a 32-bit installation, which limits the memory available to the game", install.Name)
+	default:
+		return "", fmt.Errorf("%w: %s is %s, which cannot run on %s", ErrIncompatible, install.Name, arch, hostArch)
+	}
+
+	// Legacy versions depend on java 8 internals which were removed in later versions
+	if warning == "" && requiredMajor == 8 && install.Version > 8 {
+		warning = fmt.Sprintf("%s is java %d, this version may fail to start with java newer than 8", install.Name, install.Version)
+	}
+
+	return warning, nil
+}
+
+// FindCompatible returns the registered installation best suited to run a version requiring the given major
+// java version, or nil if there is none. Installations without warnings are preferred, then the closest version.
+func FindCompatible(m Manager, hostArch string, requiredMajor int) *Installation {
+	names := m.Installations()
+	slices.Sort(names)
+
+	var best *Installation
+	var bestWarning bool
+	for _, name := range names {
+		install := m.GetInstallation(name)
+		warning, err := CheckCompatibility(install, hostArch, requiredMajor)
+		if err != nil {
+			continue
+		}
+
+		hasWarning := warning != ""
+		switch {
+		case best == nil:
+		case hasWarning != bestWarning:
+			if hasWarning {
+				continue
+			}
+		case install.Version >= best.Version:
+			continue
+		}
+		best, bestWarning = install, hasWarning
+	}
+	return best
+}
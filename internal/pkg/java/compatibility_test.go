@@ -0,0 +1,53 @@
+package java
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		install    Installation
+		hostArch   string
+		required   int
+		warning    bool
+		compatible bool
+	}{
+		{"native", Installation{Arch: "amd64", Version: 21}, "x86_64", 21, false, true},
+		{"unknown requirement", Installation{Arch: "aarch64", Version: 8}, "arm64", 0, false, true},
+		{"too old", Installation{Arch: "amd64", Version: 8}, "x86_64", 21, false, false},
+		{"emulated", Installation{Arch: "x86_64", Version: 17}, "arm64", 17, true, true},
+		{"32-bit", Installation{Arch: "x86", Version: 17}, "x86_64", 17, true, true},
+		{"foreign arch", Installation{Arch: "aarch64", Version: 17}, "x86_64", 17, false, false},
+		{"legacy on newer java", Installation{Arch: "amd64", Version: 17}, "x86_64", 8, true, true},
+		{"broken", Installation{Arch: "amd64", Version: 21, Broken: true}, "x86_64", 21, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warning, err := CheckCompatibility(&tt.install, tt.hostArch, tt.required)
+			if tt.compatible {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrIncompatible)
+			}
+			assert.Equal(t, tt.warning, warning != "")
+		})
+	}
+}
+
+func TestFindCompatible(t *testing.T) {
+	m := &fileManager{Installs: map[string]*Installation{
+		"jdk-8":        {Name: "jdk-8", Arch: "aarch64", Version: 8},
+		"jdk-17-intel": {Name: "jdk-17-intel", Arch: "x86_64", Version: 17},
+		"jdk-17":       {Name: "jdk-17", Arch: "aarch64", Version: 17},
+		"jdk-21":       {Name: "jdk-21", Arch: "aarch64", Version: 21},
+	}}
+
+	assert.Equal(t, "jdk-17", FindCompatible(m, "arm64", 17).Name)
+	assert.Equal(t, "jdk-21", FindCompatible(m, "arm64", 21).Name)
+	assert.Equal(t, "jdk-17-intel", FindCompatible(m, "x86_64", 17).Name)
+	assert.Nil(t, FindCompatible(m, "x86_64", 21))
+}
@@ -7,20 +7,31 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 )
 
-func discoverDirectory(dir string) []*Installation {
+// candidate is a possible java executable found during discovery. If name is empty, it is named after
+// the directory containing the installation.
+type candidate struct {
+	path string
+	name string
+}
+
+// discoverDirectory returns a candidate for every installation directly inside dir.
+func discoverDirectory(dir string) []candidate {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil
 	}
 
-	var result []*Installation
+	// Symlinks (eg default-java or current) are listed last, so that installations are named after their real directory
+	var result, links []candidate
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		isLink := entry.Type()&fs.ModeSymlink != 0
+		if !entry.IsDir() && !isLink {
 			continue
 		}
 
@@ -29,12 +40,40 @@ func discoverDirectory(dir string) []*Installation {
 			continue
 		}
 
+		if isLink {
+			links = append(links, candidate{path: execPath, name: entry.Name()})
+		} else {
+			result = append(result, candidate{path: execPath, name: entry.Name()})
+		}
+	}
+
+	return append(result, links...)
+}
+
+// discoverCandidates runs discovery on every candidate, skipping any which fail and any which resolve (after
+// following symlinks) to an executable already discovered. Paths of the result are resolved.
+func discoverCandidates(candidates []candidate) (result []*Installation) {
+	seen := make(map[string]bool)
+	for _, c := range candidates {
+		resolved, err := filepath.EvalSymlinks(c.path)
+		if err != nil || seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+
+		name := c.name
+		if name == "" {
+			name = path.Base(strings.TrimSuffix(filepath.ToSlash(resolved), "/"+javaExecSubPath))
+		}
+
 		// Error doesn't matter, just move on
-		install, _ := DiscoverJava(execPath, entry.Name())
+		install, err := DiscoverJava(resolved, name)
+		if err != nil {
+			continue
+		}
 		result = append(result, install)
 	}
-
-	return result
+	return
 }
 
 func DiscoverJava(executable, name string) (*Installation, error) {
@@ -63,6 +102,8 @@ func discoverParams(i *Installation) error {
 		return err
 	}
 	i.Version = int(v)
+	i.Vendor = properties["java.vendor"]
+	i.FullVersion = properties["java.runtime.version"]
 
 	if i.Name == "" {
 		i.Name = properties["java.vendor.version"]
@@ -2,11 +2,52 @@
 
 package java
 
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+)
+
 var javaExecSubPath = "bin/java"
 
+// runtimeExecSubPath is the path of the java executable inside a Mojang java runtime
+var runtimeExecSubPath = "bin/java"
+
+// runtimePlatform returns the name of the host platform in the Mojang java runtime manifest.
+func runtimePlatform() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "linux"
+	case "386":
+		return "linux-i386"
+	}
+	return ""
+}
+
 func discoverKnownPaths() (result []*Installation) {
+	var candidates []candidate
+
+	// System and package manager installs
+	for _, dir := range []string{"/usr/lib/jvm", "/usr/java", "/opt"} {
+		candidates = append(candidates, discoverDirectory(dir)...)
+	}
+
+	// SDKMAN, Gradle toolchains and IntelliJ
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		for _, dir := range []string{".sdkman/candidates/java", ".gradle/jdks", ".jdks"} {
+			candidates = append(candidates, discoverDirectory(path.Join(homeDir, dir))...)
+		}
+	}
 
-	//todo
+	if javaHome := os.Getenv("JAVA_HOME"); javaHome != "" {
+		candidates = append(candidates, candidate{path: path.Join(javaHome, javaExecSubPath)})
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir != "" {
+			candidates = append(candidates, candidate{path: path.Join(dir, "java")})
+		}
+	}
 
-	return
+	return discoverCandidates(candidates)
 }
@@ -5,18 +5,28 @@ package java
 import (
 	"os"
 	"path"
+	"runtime"
 )
 
 var javaExecSubPath = "Contents/Home/bin/java"
 
+// runtimeExecSubPath is the path of the java executable inside a Mojang java runtime
+var runtimeExecSubPath = "jre.bundle/Contents/Home/bin/java"
+
+// runtimePlatform returns the name of the host platform in the Mojang java runtime manifest.
+func runtimePlatform() string {
+	if runtime.GOARCH == "arm64" {
+		return "mac-os-arm64"
+	}
+	return "mac-os"
+}
+
 func discoverKnownPaths() (result []*Installation) {
 
 	// $HOME/Library/Java/JavaVirtualMachines
 	if homeDir, err := os.UserHomeDir(); err == nil {
 		userLibraryInstalls := path.Join(homeDir, "Library/Java/JavaVirtualMachines")
-		for _, install := range discoverDirectory(userLibraryInstalls) {
-			result = append(result, install)
-		}
+		result = discoverCandidates(discoverDirectory(userLibraryInstalls))
 	}
 
 	//todo the rest of this
@@ -1,8 +1,13 @@
 package java
 
 import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -43,3 +48,32 @@ OpenJDK 64-Bit Server VM Temurin-19.0.2+7 (build 19.0.2+7, mixed mode)`
 	require.Equal(t, "java.class.version = 63.0", result["java.class.path"])
 	require.Equal(t, "HotSpot 64-Bit Tiered Compilers", result["sun.management.compiler"])
 }
+
+func TestDiscoverCandidates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake java executable is a shell script")
+	}
+
+	// Resolved because the temp dir may itself be a symlink (eg on macOS)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	writeJava := func(name, content string) {
+		execPath := path.Join(dir, name, javaExecSubPath)
+		require.NoError(t, os.MkdirAll(path.Dir(execPath), 0755))
+		require.NoError(t, os.WriteFile(execPath, []byte(content), 0755))
+	}
+	writeJava("jdk-21", "#!/bin/sh\necho 'java.vm.specification.version = 21'\n")
+	writeJava("broken", "#!/bin/sh\nexit 1\n")
+	require.NoError(t, os.Symlink(path.Join(dir, "jdk-21"), path.Join(dir, "current")))
+
+	// The symlink is listed as a candidate, but resolves to the same installation
+	candidates := discoverDirectory(dir)
+	assert.Len(t, candidates, 3)
+	candidates = append(candidates, candidate{path: path.Join(dir, "current", javaExecSubPath)})
+
+	result := discoverCandidates(candidates)
+	require.Len(t, result, 1)
+	assert.Equal(t, "jdk-21", result[0].Name)
+	assert.Equal(t, 21, result[0].Version)
+	assert.Equal(t, path.Join(dir, "jdk-21", javaExecSubPath), result[0].Path)
+}
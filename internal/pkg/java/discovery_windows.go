@@ -2,8 +2,26 @@
 
 package java
 
+import "runtime"
+
 var javaExecSubPath = "bin/java"
 
+// runtimeExecSubPath is the path of the java executable inside a Mojang java runtime
+var runtimeExecSubPath = "bin/java"
+
+// runtimePlatform returns the name of the host platform in the Mojang java runtime manifest.
+func runtimePlatform() string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "windows-x64"
+	case "386":
+		return "windows-x86"
+	case "arm64":
+		return "windows-arm64"
+	}
+	return ""
+}
+
 func discoverKnownPaths() (result []*Installation) {
 
 	//todo
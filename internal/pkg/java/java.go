@@ -1,17 +1,24 @@
 package java
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/config"
 )
 
 var (
 	ErrInstallationNotFound = errors.New("installation not found")
+	ErrInstallationExists   = errors.New("installation already exists")
 	installsFileName        = "java.json"
 )
 
@@ -20,6 +27,11 @@ type Installation struct {
 	Path    string `json:"path"`
 	Arch    string `json:"arch"`
 	Version int    `json:"version"`
+	// Vendor and FullVersion are only informational, eg Eclipse Adoptium and 21.0.3+9
+	Vendor      string `json:"vendor,omitempty"`
+	FullVersion string `json:"full_version,omitempty"`
+	// Broken is set if the installation could not be run when last refreshed
+	Broken bool `json:"broken,omitempty"`
 }
 
 type Manager interface {
@@ -27,32 +39,52 @@ type Manager interface {
 	SetDefault(name string) error
 
 	Discover(name string) (*Installation, error)
+	// Scan searches the known installation paths for the host platform, returning new installations
+	Scan() []*Installation
+	// InstallRuntime downloads and registers a Mojang java runtime by component name or major version,
+	// or a vendor JDK as `<vendor>@<major>` (eg temurin@21)
+	InstallRuntime(ctx context.Context, name string) (*Installation, error)
 
 	Installations() []string
 	GetInstallation(name string) *Installation
+	// FindRuntime returns a working runtime installed by the manager with the given major version, or nil
+	FindRuntime(majorVersion int) *Installation
+	// Remove forgets an installation, deleting it if it is a runtime installed by the manager
+	Remove(name string) error
+	Rename(name, newName string) error
+	// Refresh runs discovery again on every installation, marking those which fail as broken
+	Refresh()
 
 	Save() error
 }
 
 type fileManager struct {
-	Path     string                   `json:"-"`
+	Path        string       `json:"-"`
+	Client      *http.Client `json:"-"`
+	RuntimesDir string       `json:"-"`
+	AdoptiumUrl string       `json:"-"`
+
 	Default  string                   `json:"default"`
 	Installs map[string]*Installation `json:"installs"`
 }
 
-func NewManager(dataDir string) (Manager, error) {
+func NewManager(dataDir string, config *config.Config, client *http.Client) (Manager, error) {
 	javaFile := path.Join(dataDir, installsFileName)
+	runtimesDir := path.Join(dataDir, runtimesDirName)
+	adoptiumUrl := config.Java.AdoptiumUrl
+	if adoptiumUrl == "" {
+		adoptiumUrl = defaultAdoptiumUrl
+	}
 	if _, err := os.Stat(javaFile); errors.Is(err, fs.ErrNotExist) {
-		installs := make(map[string]*Installation)
-
-		for _, i := range discoverKnownPaths() {
-			installs[strings.ToLower(i.Name)] = i
+		manager := &fileManager{
+			Path:        javaFile,
+			Client:      client,
+			RuntimesDir: runtimesDir,
+			AdoptiumUrl: adoptiumUrl,
+			Installs:    make(map[string]*Installation),
 		}
-
-		return &fileManager{
-			Path:     javaFile,
-			Installs: installs,
-		}, nil
+		manager.Scan()
+		return manager, nil
 	}
 
 	f, err := os.Open(javaFile)
@@ -61,7 +93,7 @@ func NewManager(dataDir string) (Manager, error) {
 	}
 	defer f.Close()
 
-	manager := fileManager{Path: javaFile}
+	manager := fileManager{Path: javaFile, Client: client, RuntimesDir: runtimesDir, AdoptiumUrl: adoptiumUrl}
 	if err := json.NewDecoder(f).Decode(&manager); err != nil {
 		return nil, fmt.Errorf("failed to read %s: %w", installsFileName, err)
 	}
@@ -95,6 +127,32 @@ func (m *fileManager) Discover(exec string) (*Installation, error) {
 	return install, err
 }
 
+func (m *fileManager) Scan() (added []*Installation) {
+	// Existing installations are compared by their resolved path, the same as discovered installations
+	known := make(map[string]bool)
+	for _, install := range m.Installs {
+		if resolved, err := filepath.EvalSymlinks(install.Path); err == nil {
+			known[resolved] = true
+		}
+		known[install.Path] = true
+	}
+
+	for _, install := range discoverKnownPaths() {
+		if known[install.Path] {
+			continue
+		}
+
+		// Different directories may contain installations with the same name
+		name := install.Name
+		for i := 2; m.Installs[strings.ToLower(install.Name)] != nil; i++ {
+			install.Name = fmt.Sprintf("%s-%d", name, i)
+		}
+		m.Installs[strings.ToLower(install.Name)] = install
+		added = append(added, install)
+	}
+	return
+}
+
 func (m *fileManager) Installations() (result []string) {
 	for k := range m.Installs {
 		result = append(result, k)
@@ -106,6 +164,76 @@ func (m *fileManager) GetInstallation(name string) *Installation {
 	return m.Installs[strings.ToLower(name)]
 }
 
+func (m *fileManager) FindRuntime(majorVersion int) *Installation {
+	// Sorted for a stable result
+	names := m.Installations()
+	slices.Sort(names)
+	for _, name := range names {
+		install := m.Installs[name]
+		if install.Version != majorVersion || install.Broken {
+			continue
+		}
+		if rel, err := filepath.Rel(m.RuntimesDir, install.Path); err == nil && filepath.IsLocal(rel) {
+			return install
+		}
+	}
+	return nil
+}
+
+func (m *fileManager) Remove(name string) error {
+	name = strings.ToLower(name)
+	install, ok := m.Installs[name]
+	if !ok {
+		return ErrInstallationNotFound
+	}
+
+	// Runtimes installed by mc are deleted along with the entry, eg runtimes/temurin-21/bin/java
+	if rel, err := filepath.Rel(m.RuntimesDir, install.Path); err == nil && filepath.IsLocal(rel) {
+		runtimeDir := strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+		if err := os.RemoveAll(filepath.Join(m.RuntimesDir, runtimeDir)); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", install.Name, err)
+		}
+	}
+
+	delete(m.Installs, name)
+	if m.Default == name {
+		m.Default = ""
+	}
+	return nil
+}
+
+func (m *fileManager) Rename(name, newName string) error {
+	name, newKey := strings.ToLower(name), strings.ToLower(newName)
+	install, ok := m.Installs[name]
+	if !ok {
+		return ErrInstallationNotFound
+	}
+	if _, ok := m.Installs[newKey]; ok && newKey != name {
+		return ErrInstallationExists
+	}
+
+	delete(m.Installs, name)
+	install.Name = newName
+	m.Installs[newKey] = install
+	if m.Default == name {
+		m.Default = newKey
+	}
+	return nil
+}
+
+func (m *fileManager) Refresh() {
+	for _, install := range m.Installs {
+		// Update a copy, so that a failure does not lose the last known parameters
+		updated := *install
+		if err := discoverParams(&updated); err != nil {
+			install.Broken = true
+			continue
+		}
+		updated.Broken = false
+		*install = updated
+	}
+}
+
 func (m *fileManager) Save() error {
 	f, err := os.OpenFile(m.Path, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
 	if err != nil {
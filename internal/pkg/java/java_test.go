@@ -0,0 +1,62 @@
+package java
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerMaintenance(t *testing.T) {
+	dataDir := t.TempDir()
+	runtimesDir := path.Join(dataDir, runtimesDirName)
+	managedPath := path.Join(runtimesDir, "temurin-21", "bin", "java")
+	require.NoError(t, os.MkdirAll(path.Dir(managedPath), 0755))
+
+	m := &fileManager{
+		RuntimesDir: runtimesDir,
+		Default:     "system",
+		Installs: map[string]*Installation{
+			"system":     {Name: "system", Path: path.Join(dataDir, "missing", "java"), Version: 17},
+			"temurin-21": {Name: "temurin-21", Path: managedPath, Version: 21},
+		},
+	}
+
+	// Broken installations keep their last known version
+	m.Refresh()
+	assert.True(t, m.GetInstallation("system").Broken)
+	assert.Equal(t, 17, m.GetInstallation("system").Version)
+
+	require.NoError(t, m.Rename("system", "Old"))
+	assert.Nil(t, m.GetInstallation("system"))
+	assert.Equal(t, "Old", m.GetInstallation("old").Name)
+	assert.Equal(t, "old", m.GetDefault())
+	assert.ErrorIs(t, m.Rename("old", "temurin-21"), ErrInstallationExists)
+	assert.ErrorIs(t, m.Rename("missing", "other"), ErrInstallationNotFound)
+
+	// Runtimes installed by the manager are deleted, others are only forgotten
+	require.NoError(t, m.Remove("temurin-21"))
+	assert.NoDirExists(t, path.Join(runtimesDir, "temurin-21"))
+	assert.DirExists(t, runtimesDir)
+	require.NoError(t, m.Remove("old"))
+	assert.Empty(t, m.GetDefault())
+	assert.ErrorIs(t, m.Remove("old"), ErrInstallationNotFound)
+}
+
+func TestFindRuntime(t *testing.T) {
+	runtimesDir := path.Join(t.TempDir(), runtimesDirName)
+	m := &fileManager{
+		RuntimesDir: runtimesDir,
+		Installs: map[string]*Installation{
+			"system":             {Name: "system", Path: "/usr/bin/java", Version: 17},
+			"java-runtime-gamma": {Name: "java-runtime-gamma", Path: path.Join(runtimesDir, "java-runtime-gamma", "bin", "java"), Version: 17},
+			"java-runtime-delta": {Name: "java-runtime-delta", Path: path.Join(runtimesDir, "java-runtime-delta", "bin", "java"), Version: 21, Broken: true},
+		},
+	}
+
+	assert.Equal(t, "java-runtime-gamma", m.FindRuntime(17).Name)
+	assert.Nil(t, m.FindRuntime(21), "broken runtimes are ignored")
+	assert.Nil(t, m.FindRuntime(8))
+}
@@ -0,0 +1,248 @@
+package java
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const (
+	runtimeManifestUrl = "https://launchermeta.mojang.com/v1/products/java-runtime/2ec0cc96c44e5a76b9c8b7c39df7210883d12871/all.json"
+	runtimesDirName    = "runtimes"
+)
+
+var (
+	ErrUnknownRuntime      = errors.New("unknown java runtime")
+	ErrUnsupportedPlatform = errors.New("java runtimes are not available for this platform")
+)
+
+type (
+	// Response from runtimeManifestUrl, a mapping of platform to component to runtime (usually only one)
+	runtimeManifest map[string]map[string][]*runtimeEntry
+	runtimeEntry    struct {
+		Manifest util.FileDownload `json:"manifest"`
+		Version  struct {
+			Name     string    `json:"name"`
+			Released time.Time `json:"released"`
+		} `json:"version"`
+	}
+
+	// Response from runtimeEntry.Manifest
+	runtimeFileManifest struct {
+		Files map[string]*runtimeFile `json:"files"`
+	}
+	runtimeFile struct {
+		Type       string `json:"type"` // file, directory or link
+		Executable bool   `json:"executable"`
+		Target     string `json:"target"`
+		Downloads  *struct {
+			Raw util.FileDownload `json:"raw"`
+		} `json:"downloads"`
+	}
+)
+
+// InstallRuntime installs a vendor JDK if name is `<vendor>@<major>`, otherwise a Mojang java runtime.
+func (m *fileManager) InstallRuntime(ctx context.Context, name string) (*Installation, error) {
+	if vendor, version, ok := strings.Cut(name, "@"); ok {
+		switch strings.ToLower(vendor) {
+		case "temurin", "adoptium":
+			return m.installTemurin(ctx, version)
+		default:
+			return nil, fmt.Errorf("%w: unknown vendor %s", ErrUnknownRuntime, vendor)
+		}
+	}
+	return m.installMojangRuntime(ctx, name)
+}
+
+// installMojangRuntime downloads a Mojang java runtime for the host platform and registers it as an
+// installation named after the runtime component. The runtime may be given as a component (eg
+// java-runtime-delta) or a major java version. If empty, the runtime with the newest java version is installed.
+//
+// Files which are already present are not downloaded again, so an interrupted install can be resumed.
+func (m *fileManager) installMojangRuntime(ctx context.Context, name string) (*Installation, error) {
+	platform := runtimePlatform()
+	if platform == "" {
+		return nil, ErrUnsupportedPlatform
+	}
+
+	var manifest runtimeManifest
+	if err := util.FetchJson(ctx, m.Client, runtimeManifestUrl, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to fetch java runtime manifest: %w", err)
+	}
+	component, entry, err := resolveRuntime(manifest[platform], name)
+	if err != nil {
+		return nil, err
+	}
+
+	var files runtimeFileManifest
+	if err := util.FetchJson(ctx, m.Client, entry.Manifest.Url, &files); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s file manifest: %w", component, err)
+	}
+
+	runtimeDir := path.Join(m.RuntimesDir, component)
+	if err := installRuntimeFiles(ctx, m.Client, runtimeDir, &files); err != nil {
+		return nil, fmt.Errorf("failed to install %s: %w", component, err)
+	}
+
+	install, err := DiscoverJava(path.Join(runtimeDir, runtimeExecSubPath), component)
+	if err != nil {
+		return nil, err
+	}
+	m.Installs[strings.ToLower(install.Name)] = install
+	return install, nil
+}
+
+// resolveRuntime finds the runtime with the given component name or major version. If multiple components
+// provide the same major version, the most recently released is used.
+func resolveRuntime(runtimes map[string][]*runtimeEntry, name string) (string, *runtimeEntry, error) {
+	if entries := runtimes[name]; len(entries) > 0 {
+		return name, entries[0], nil
+	}
+
+	major, err := strconv.Atoi(name)
+	if name != "" && err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownRuntime, name)
+	}
+
+	// Sorted for a stable result when release times are equal
+	components := make([]string, 0, len(runtimes))
+	for component := range runtimes {
+		components = append(components, component)
+	}
+	slices.Sort(components)
+
+	var resultComponent string
+	var result *runtimeEntry
+	for _, component := range components {
+		entries := runtimes[component]
+		if len(entries) == 0 {
+			continue
+		}
+		entry := entries[0]
+		entryMajor := runtimeMajorVersion(entry.Version.Name)
+		if name == "" {
+			// Newest java version, ignoring snapshot runtimes
+			if strings.HasSuffix(component, "-snapshot") {
+				continue
+			}
+			if result != nil && entryMajor <= runtimeMajorVersion(result.Version.Name) {
+				continue
+			}
+		} else if entryMajor != major || (result != nil && !entry.Version.Released.After(result.Version.Released)) {
+			continue
+		}
+		resultComponent, result = component, entry
+	}
+
+	if result == nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrUnknownRuntime, name)
+	}
+	return resultComponent, result, nil
+}
+
+// runtimeMajorVersion returns the major java version of a runtime version name, eg 17.0.8 or 8u51.
+func runtimeMajorVersion(name string) int {
+	name = strings.TrimPrefix(name, "1.")
+	end := strings.IndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+	if end == -1 {
+		end = len(name)
+	}
+	major, _ := strconv.Atoi(name[:end])
+	return major
+}
+
+// installRuntimeFiles creates every file in the file manifest inside dir. Files are verified against their
+// hash when downloaded, and links are created once every file is present.
+func installRuntimeFiles(ctx context.Context, client *http.Client, dir string, manifest *runtimeFileManifest) error {
+	// The first failure cancels the remaining downloads
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	openConns := make(chan struct{}, 16)
+	for i := 0; i < 16; i++ {
+		openConns <- struct{}{}
+	}
+
+	wg := sync.WaitGroup{}
+	for name, file := range manifest.Files {
+		target, err := util.SafeJoin(dir, name)
+		if err != nil {
+			cancel(err)
+			break
+		}
+
+		if file.Type == "directory" {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				cancel(err)
+				break
+			}
+			continue
+		}
+		if file.Type != "file" || file.Downloads == nil {
+			continue
+		}
+
+		// Read from connection pool, giving up if the install has been cancelled
+		select {
+		case <-openConns:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(name, target string, file *runtimeFile) {
+			defer wg.Done()
+			defer func() {
+				openConns <- struct{}{}
+			}()
+
+			if err := util.Download(ctx, client, target, file.Downloads.Raw); err != nil {
+				cancel(fmt.Errorf("failed to download %s: %w", name, err))
+				return
+			}
+			if file.Executable {
+				if err := os.Chmod(target, 0755); err != nil {
+					cancel(err)
+				}
+			}
+		}(name, target, file)
+	}
+	wg.Wait()
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+
+	for name, file := range manifest.Files {
+		if file.Type != "link" {
+			continue
+		}
+		target, err := util.SafeJoin(dir, name)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Lstat(target); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(file.Target, target); err != nil {
+			return fmt.Errorf("failed to link %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package java
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRuntime(t *testing.T) {
+	entry := func(version string, released time.Time) []*runtimeEntry {
+		e := &runtimeEntry{}
+		e.Version.Name = version
+		e.Version.Released = released
+		return []*runtimeEntry{e}
+	}
+	runtimes := map[string][]*runtimeEntry{
+		"jre-legacy":                  entry("8u51", time.Unix(1, 0)),
+		"java-runtime-beta":           entry("17.0.1.12.1", time.Unix(2, 0)),
+		"java-runtime-gamma":          entry("17.0.8", time.Unix(3, 0)),
+		"java-runtime-delta":          entry("21.0.3", time.Unix(4, 0)),
+		"java-runtime-gamma-snapshot": entry("22.0.1", time.Unix(5, 0)),
+		"minecraft-java-exe":          {},
+	}
+
+	tests := []struct {
+		name      string
+		component string
+	}{
+		{"java-runtime-beta", "java-runtime-beta"},
+		{"17", "java-runtime-gamma"},
+		{"8", "jre-legacy"},
+		{"", "java-runtime-delta"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, _, err := resolveRuntime(runtimes, tt.name)
+			require.NoError(t, err)
+			assert.Equal(t, tt.component, component)
+		})
+	}
+
+	_, _, err := resolveRuntime(runtimes, "11")
+	assert.ErrorIs(t, err, ErrUnknownRuntime)
+	_, _, err = resolveRuntime(runtimes, "minecraft-java-exe")
+	assert.ErrorIs(t, err, ErrUnknownRuntime)
+}
+
+func TestInstallRuntimeFiles(t *testing.T) {
+	content := []byte("#!/bin/sh")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	file := func(executable bool, hash string) *runtimeFile {
+		f := &runtimeFile{Type: "file", Executable: executable}
+		f.Downloads = &struct {
+			Raw util.FileDownload `json:"raw"`
+		}{util.FileDownload{Url: server.URL, Sha1: hash}}
+		return f
+	}
+	manifest := &runtimeFileManifest{Files: map[string]*runtimeFile{
+		"bin":           {Type: "directory"},
+		"bin/java":      file(true, fmt.Sprintf("%x", sha1.Sum(content))),
+		"lib/data":      file(false, ""),
+		"legal/java":    {Type: "link", Target: "../bin/java"},
+		"legal/ignored": {Type: "unknown"},
+	}}
+
+	dir := t.TempDir()
+	require.NoError(t, installRuntimeFiles(context.Background(), server.Client(), dir, manifest))
+
+	info, err := os.Stat(path.Join(dir, "bin", "java"))
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode().Perm()&0100)
+	info, err = os.Stat(path.Join(dir, "lib", "data"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode().Perm()&0100)
+	target, err := os.Readlink(path.Join(dir, "legal", "java"))
+	require.NoError(t, err)
+	assert.Equal(t, "../bin/java", target)
+
+	// A hash mismatch fails the install
+	manifest.Files["lib/other"] = file(false, "invalid")
+	assert.Error(t, installRuntimeFiles(context.Background(), server.Client(), dir, manifest))
+}
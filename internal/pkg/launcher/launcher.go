@@ -0,0 +1,229 @@
+// Package launcher reads and writes the installations of the vanilla Minecraft launcher.
+package launcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const (
+	ProfilesFileName = "launcher_profiles.json"
+
+	// Installation types, the latest types have no version id
+	TypeCustom         = "custom"
+	TypeLatestRelease  = "latest-release"
+	TypeLatestSnapshot = "latest-snapshot"
+)
+
+// Installation is a launcher installation (called a profile in launcher_profiles.json)
+type Installation struct {
+	Name          string `json:"name,omitempty"`
+	Type          string `json:"type,omitempty"`
+	Created       string `json:"created,omitempty"`
+	LastUsed      string `json:"lastUsed,omitempty"`
+	Icon          string `json:"icon,omitempty"`
+	LastVersionId string `json:"lastVersionId,omitempty"`
+	GameDir       string `json:"gameDir,omitempty"`
+	JavaArgs      string `json:"javaArgs,omitempty"`
+	JavaDir       string `json:"javaDir,omitempty"` // The java executable, not a directory
+}
+
+// Profiles is the content of launcher_profiles.json. Unknown fields are kept when saved.
+type Profiles struct {
+	Path          string
+	Installations map[string]*Installation
+
+	raw         map[string]json.RawMessage
+	rawProfiles map[string]map[string]any
+}
+
+// ReadProfiles reads launcher_profiles.json from the given Minecraft directory, which may not exist.
+func ReadProfiles(minecraftDir string) (*Profiles, error) {
+	p := &Profiles{
+		Path:          path.Join(minecraftDir, ProfilesFileName),
+		Installations: make(map[string]*Installation),
+		raw:           make(map[string]json.RawMessage),
+		rawProfiles:   make(map[string]map[string]any),
+	}
+
+	data, err := os.ReadFile(p.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &p.raw); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ProfilesFileName, err)
+	}
+	if profiles, ok := p.raw["profiles"]; ok {
+		if err := json.Unmarshal(profiles, &p.rawProfiles); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", ProfilesFileName, err)
+		}
+		if err := json.Unmarshal(profiles, &p.Installations); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", ProfilesFileName, err)
+		}
+	}
+	return p, nil
+}
+
+// Save writes the installations back to launcher_profiles.json.
+func (p *Profiles) Save() error {
+	profiles := make(map[string]map[string]any)
+	for key, install := range p.Installations {
+		// Start from the existing entry, so that fields unknown to mc are kept
+		entry := p.rawProfiles[key]
+		if entry == nil {
+			entry = make(map[string]any)
+		}
+		for _, field := range []string{"name", "type", "lastVersionId", "gameDir", "javaArgs", "javaDir"} {
+			delete(entry, field)
+		}
+
+		var fields map[string]any
+		data, err := json.Marshal(install)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		for field, value := range fields {
+			entry[field] = value
+		}
+		profiles[key] = entry
+	}
+
+	data, err := json.Marshal(profiles)
+	if err != nil {
+		return err
+	}
+	p.raw["profiles"] = data
+	if _, ok := p.raw["version"]; !ok {
+		p.raw["version"] = json.RawMessage("3")
+	}
+
+	data, err = json.MarshalIndent(p.raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(p.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p.Path, data, 0644)
+}
+
+// VersionType returns the profile type and loader version of an installed version, based on the naming of
+// the loader installers. Versions without a parent are vanilla, and other versions are custom.
+func VersionType(spec *gameModel.VersionSpec) (profile.Type, string) {
+	id := spec.Id
+	switch {
+	case spec.InheritsFrom == "":
+		return profile.Vanilla, ""
+	case strings.HasPrefix(id, "fabric-loader-"):
+		return profile.Fabric, strings.TrimSuffix(strings.TrimPrefix(id, "fabric-loader-"), "-"+spec.InheritsFrom)
+	case strings.HasPrefix(id, "quilt-loader-"):
+		return profile.Quilt, strings.TrimSuffix(strings.TrimPrefix(id, "quilt-loader-"), "-"+spec.InheritsFrom)
+	case strings.HasPrefix(id, "neoforge-"):
+		return profile.NeoForge, strings.TrimPrefix(id, "neoforge-")
+	case strings.Contains(id, "-forge-"):
+		_, loader, _ := strings.Cut(id, "-forge-")
+		return profile.Forge, loader
+	}
+	return profile.Custom, ""
+}
+
+// CopyVersion copies the spec and jar of a version (and its parents) between versions directories, skipping
+// files which already exist. An error wrapping fs.ErrNotExist is returned if the spec is not in fromDir.
+// Parent versions which are missing are left for the installer to download.
+func CopyVersion(fromDir, toDir, id string) error {
+	for id != "" {
+		from, to := path.Join(fromDir, id), path.Join(toDir, id)
+		specFile := fmt.Sprintf("%s.json", id)
+
+		var spec gameModel.VersionSpec
+		if err := util.ReadFile(path.Join(from, specFile), &spec); err != nil {
+			return err
+		}
+		for _, file := range []string{specFile, fmt.Sprintf("%s.jar", id)} {
+			if _, err := os.Stat(path.Join(to, file)); err == nil {
+				continue
+			}
+			err := util.CopyFile(path.Join(from, file), path.Join(to, file))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+
+		if _, err := os.Stat(path.Join(fromDir, spec.InheritsFrom, fmt.Sprintf("%s.json", spec.InheritsFrom))); err != nil {
+			break
+		}
+		id = spec.InheritsFrom
+	}
+	return nil
+}
+
+// ParseJavaArgs splits launcher java arguments, separating the heap size arguments (in megabytes).
+func ParseJavaArgs(javaArgs string) (args []string, minMemory, maxMemory int) {
+	for _, arg := range util.SplitArgs(javaArgs) {
+		// Invalid sizes are kept as is, java will report them
+		if size, ok := strings.CutPrefix(arg, "-Xms"); ok && parseMemory(size) > 0 {
+			minMemory = parseMemory(size)
+			continue
+		}
+		if size, ok := strings.CutPrefix(arg, "-Xmx"); ok && parseMemory(size) > 0 {
+			maxMemory = parseMemory(size)
+			continue
+		}
+		args = append(args, arg)
+	}
+	return
+}
+
+// FormatJavaArgs joins java arguments and heap sizes (in megabytes) into launcher java arguments.
+func FormatJavaArgs(args []string, minMemory, maxMemory int) string {
+	var result []string
+	if minMemory > 0 {
+		result = append(result, fmt.Sprintf("-Xms%dM", minMemory))
+	}
+	if maxMemory > 0 {
+		result = append(result, fmt.Sprintf("-Xmx%dM", maxMemory))
+	}
+	return util.JoinArgs(append(result, args...))
+}
+
+// parseMemory converts a java memory size (eg 2G or 512m) to megabytes, returning 0 if it is invalid.
+func parseMemory(size string) int {
+	bytesPerUnit := int64(1) // No suffix is a size in bytes
+	if size != "" {
+		switch size[len(size)-1] {
+		case 'k', 'K':
+			bytesPerUnit = 1 << 10
+		case 'm', 'M':
+			bytesPerUnit = 1 << 20
+		case 'g', 'G':
+			bytesPerUnit = 1 << 30
+		case 't', 'T':
+			bytesPerUnit = 1 << 40
+		}
+	}
+	if bytesPerUnit != 1 {
+		size = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return int(value * bytesPerUnit >> 20)
+}
@@ -0,0 +1,86 @@
+package launcher
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gameModel "github.com/mworzala/mc/internal/pkg/game/model"
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	original := `{
+		"profiles": {
+			"abc": {"name": "Modded", "type": "custom", "lastVersionId": "fabric-loader-0.15.3-1.20.4", "javaArgs": "-Xmx2G", "resolution": {"width": 854}}
+		},
+		"settings": {"enableSnapshots": true},
+		"version": 3
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ProfilesFileName), []byte(original), 0644))
+
+	profiles, err := ReadProfiles(dir)
+	require.NoError(t, err)
+	require.Equal(t, "fabric-loader-0.15.3-1.20.4", profiles.Installations["abc"].LastVersionId)
+
+	profiles.Installations["abc"].JavaArgs = ""
+	profiles.Installations["mc-test"] = &Installation{Name: "test", Type: TypeCustom, LastVersionId: "1.20.4"}
+	require.NoError(t, profiles.Save())
+
+	var saved map[string]any
+	data, err := os.ReadFile(filepath.Join(dir, ProfilesFileName))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &saved))
+	require.Equal(t, map[string]any{"enableSnapshots": true}, saved["settings"])
+	abc := saved["profiles"].(map[string]any)["abc"].(map[string]any)
+	require.Equal(t, map[string]any{"width": float64(854)}, abc["resolution"])
+	require.NotContains(t, abc, "javaArgs")
+	require.Contains(t, saved["profiles"], "mc-test")
+}
+
+func TestVersionType(t *testing.T) {
+	tests := []struct {
+		spec   gameModel.VersionSpec
+		typ    profile.Type
+		loader string
+	}{
+		{gameModel.VersionSpec{Id: "1.20.4"}, profile.Vanilla, ""},
+		{gameModel.VersionSpec{Id: "fabric-loader-0.15.3-1.20.4", InheritsFrom: "1.20.4"}, profile.Fabric, "0.15.3"},
+		{gameModel.VersionSpec{Id: "quilt-loader-0.23.0-1.20.4", InheritsFrom: "1.20.4"}, profile.Quilt, "0.23.0"},
+		{gameModel.VersionSpec{Id: "1.20.4-forge-49.0.30", InheritsFrom: "1.20.4"}, profile.Forge, "49.0.30"},
+		{gameModel.VersionSpec{Id: "neoforge-20.4.190", InheritsFrom: "1.20.4"}, profile.NeoForge, "20.4.190"},
+		{gameModel.VersionSpec{Id: "optifine", InheritsFrom: "1.20.4"}, profile.Custom, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.spec.Id, func(t *testing.T) {
+			typ, loader := VersionType(&test.spec)
+			require.Equal(t, test.typ, typ)
+			require.Equal(t, test.loader, loader)
+		})
+	}
+}
+
+func TestJavaArgs(t *testing.T) {
+	args, minMemory, maxMemory := ParseJavaArgs("-Xms512m -Xmx2G -XX:+UseG1GC -Xmxfoo")
+	require.Equal(t, []string{"-XX:+UseG1GC", "-Xmxfoo"}, args)
+	require.Equal(t, 512, minMemory)
+	require.Equal(t, 2048, maxMemory)
+	require.Equal(t, "-Xms512M -Xmx2048M -XX:+UseG1GC -Xmxfoo", FormatJavaArgs(args, minMemory, maxMemory))
+}
+
+func TestCopyVersion(t *testing.T) {
+	from, to := t.TempDir(), t.TempDir()
+	for id, spec := range map[string]string{"fabric": `{"id":"fabric","inheritsFrom":"1.20.4"}`, "1.20.4": `{"id":"1.20.4"}`} {
+		require.NoError(t, os.MkdirAll(filepath.Join(from, id), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(from, id, id+".json"), []byte(spec), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(from, "1.20.4", "1.20.4.jar"), []byte("jar"), 0644))
+
+	require.NoError(t, CopyVersion(from, to, "fabric"))
+	require.FileExists(t, filepath.Join(to, "fabric", "fabric.json"))
+	require.FileExists(t, filepath.Join(to, "1.20.4", "1.20.4.jar"))
+	require.ErrorIs(t, CopyVersion(from, to, "missing"), os.ErrNotExist)
+}
@@ -1,6 +1,7 @@
 package modrinth
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -28,20 +29,20 @@ type Client struct {
 	timeout    time.Duration
 }
 
-func NewClient(idVersion string) *Client {
+func NewClient(idVersion string, httpClient *http.Client) *Client {
 	return &Client{
 		baseUrl:    prodUrl,
 		userAgent:  util.MakeUserAgent(idVersion),
-		httpClient: http.DefaultClient,
+		httpClient: httpClient,
 		timeout:    10 * time.Second,
 	}
 }
 
-func NewStagingClient() *Client {
+func NewStagingClient(httpClient *http.Client) *Client {
 	return &Client{
 		baseUrl:    stagingUrl,
 		userAgent:  util.MakeUserAgent("dev"),
-		httpClient: http.DefaultClient,
+		httpClient: httpClient,
 		timeout:    10 * time.Second,
 	}
 }
@@ -55,7 +56,26 @@ func get[T any](c *Client, ctx context.Context, endpoint string, params url.Valu
 	if err != nil {
 		return nil, err
 	}
+	return do[T](c, req)
+}
+
+func post[T any](c *Client, ctx context.Context, endpoint string, body any) (*T, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseUrl+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return do[T](c, req)
+}
 
+func do[T any](c *Client, req *http.Request) (*T, error) {
 	res, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
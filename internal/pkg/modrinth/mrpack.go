@@ -0,0 +1,256 @@
+package modrinth
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const (
+	packIndexFileName     = "modrinth.index.json"
+	overridesDir          = "overrides/"
+	clientOverridesDir    = "client-overrides/"
+	serverOverridesDir    = "server-overrides/"
+	supportedPackFormat   = 1
+	maxConcurrentDownload = 16
+)
+
+// Dependency ids in a pack index
+const (
+	DependencyMinecraft = "minecraft"
+	DependencyForge     = "forge"
+	DependencyNeoForge  = "neoforge"
+	DependencyFabric    = "fabric-loader"
+	DependencyQuilt     = "quilt-loader"
+)
+
+var (
+	ErrInvalidPack = errors.New("invalid modpack")
+
+	// Hosts which pack files may be downloaded from, as required by the mrpack format
+	allowedDownloadHosts = map[string]bool{
+		"cdn.modrinth.com":          true,
+		"github.com":                true,
+		"raw.githubusercontent.com": true,
+		"gitlab.com":                true,
+	}
+)
+
+// PackIndex is the modrinth.index.json file of a .mrpack modpack.
+type PackIndex struct {
+	FormatVersion int               `json:"formatVersion"`
+	Game          string            `json:"game"`
+	VersionID     string            `json:"versionId"`
+	Name          string            `json:"name"`
+	Summary       string            `json:"summary,omitempty"`
+	Files         []*PackFile       `json:"files"`
+	Dependencies  map[string]string `json:"dependencies"`
+}
+
+type PackFile struct {
+	Path      string            `json:"path"`
+	Hashes    map[string]string `json:"hashes"` // sha1 and sha512
+	Env       *PackEnv          `json:"env,omitempty"`
+	Downloads []string          `json:"downloads"`
+	FileSize  int64             `json:"fileSize"`
+}
+
+// PackEnv is the support of a pack file on each side. Files without an env are required on both.
+type PackEnv struct {
+	Client SupportStatus `json:"client"`
+	Server SupportStatus `json:"server"`
+}
+
+// PackOverride is a local file added to a pack as an override, rather than downloaded.
+type PackOverride struct {
+	Path string // The path in the game directory, eg config/sodium-options.json
+	File string
+	Env  *PackEnv
+}
+
+// ClientSupported returns false if the file is marked as unsupported on the client.
+func (f *PackFile) ClientSupported() bool {
+	return f.Env == nil || f.Env.Client != Unsupported
+}
+
+// ReadPack reads the index of a .mrpack archive.
+func ReadPack(r *zip.Reader) (*PackIndex, error) {
+	data, err := util.ReadZipFile(r, packIndexFileName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidPack, packIndexFileName)
+	}
+
+	var index PackIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPack, err)
+	}
+	if index.FormatVersion != supportedPackFormat {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidPack, index.FormatVersion)
+	}
+	if index.Game != "minecraft" {
+		return nil, fmt.Errorf("%w: unsupported game %s", ErrInvalidPack, index.Game)
+	}
+	if index.Dependencies[DependencyMinecraft] == "" {
+		return nil, fmt.Errorf("%w: missing minecraft dependency", ErrInvalidPack)
+	}
+	return &index, nil
+}
+
+// InstallPack downloads the client files of the pack into dir, verifying their hashes, and then applies the
+// overrides and client overrides. Files which already exist are not downloaded again.
+func InstallPack(ctx context.Context, client *http.Client, r *zip.Reader, index *PackIndex, dir string) error {
+	if err := downloadPackFiles(ctx, client, index, dir); err != nil {
+		return err
+	}
+
+	// Client overrides are applied last, replacing the common overrides
+	if err := util.ExtractZip(r, overridesDir, dir, false); err != nil {
+		return err
+	}
+	return util.ExtractZip(r, clientOverridesDir, dir, false)
+}
+
+func downloadPackFiles(ctx context.Context, client *http.Client, index *PackIndex, dir string) error {
+	// The first failure cancels the remaining downloads
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	openConns := make(chan struct{}, maxConcurrentDownload)
+	for i := 0; i < maxConcurrentDownload; i++ {
+		openConns <- struct{}{}
+	}
+
+	wg := sync.WaitGroup{}
+	for _, file := range index.Files {
+		if !file.ClientSupported() {
+			continue
+		}
+		target, err := util.SafeJoin(dir, file.Path)
+		if err != nil {
+			cancel(err)
+			break
+		}
+		if file.Hashes["sha1"] == "" {
+			cancel(fmt.Errorf("%w: missing sha1 for %s", ErrInvalidPack, file.Path))
+			break
+		}
+
+		// Read from connection pool, giving up if the install has been cancelled
+		select {
+		case <-openConns:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(file *PackFile, target string) {
+			defer wg.Done()
+			defer func() {
+				openConns <- struct{}{}
+			}()
+
+			if err := downloadPackFile(ctx, client, file, target); err != nil {
+				cancel(fmt.Errorf("failed to download %s: %w", file.Path, err))
+			}
+		}(file, target)
+	}
+	wg.Wait()
+	return context.Cause(ctx)
+}
+
+// downloadPackFile tries each download url of the file in order, until one succeeds.
+func downloadPackFile(ctx context.Context, client *http.Client, file *PackFile, target string) error {
+	err := fmt.Errorf("%w: no downloads", ErrInvalidPack)
+	for _, downloadUrl := range file.Downloads {
+		if u, parseErr := url.Parse(downloadUrl); parseErr != nil || u.Scheme != "https" || !allowedDownloadHosts[u.Hostname()] {
+			err = fmt.Errorf("%w: download not allowed from %s", ErrInvalidPack, downloadUrl)
+			continue
+		}
+
+		dl := util.FileDownload{Url: downloadUrl, Sha1: file.Hashes["sha1"], Size: file.FileSize}
+		if err = util.Download(ctx, client, target, dl); err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// WritePack writes a .mrpack archive with the given index and overrides. Overrides which are unsupported on
+// one side are written to the overrides directory of the other side.
+func WritePack(w io.Writer, index *PackIndex, overrides []*PackOverride) error {
+	index.FormatVersion = supportedPackFormat
+	index.Game = "minecraft"
+
+	zw := zip.NewWriter(w)
+	iw, err := zw.Create(packIndexFileName)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(iw)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(index); err != nil {
+		return err
+	}
+
+	for _, override := range overrides {
+		dir := overridesDir
+		if override.Env != nil && override.Env.Server == Unsupported {
+			dir = clientOverridesDir
+		} else if override.Env != nil && override.Env.Client == Unsupported {
+			dir = serverOverridesDir
+		}
+		if err := addFileToZip(zw, override.File, path.Join(dir, override.Path)); err != nil {
+			return fmt.Errorf("failed to add %s: %w", override.Path, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+func addFileToZip(zw *zip.Writer, file, name string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// HashFile returns the hashes of a file as used in a pack index, and its size.
+func HashFile(file string) (map[string]string, int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	sha1Hash, sha512Hash := sha1.New(), sha512.New()
+	size, err := io.Copy(io.MultiWriter(sha1Hash, sha512Hash), f)
+	if err != nil {
+		return nil, 0, err
+	}
+	return map[string]string{
+		"sha1":   fmt.Sprintf("%x", sha1Hash.Sum(nil)),
+		"sha512": fmt.Sprintf("%x", sha512Hash.Sum(nil)),
+	}, size, nil
+}
@@ -0,0 +1,112 @@
+package modrinth
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallPack(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+	serverUrl, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	allowedDownloadHosts[serverUrl.Hostname()] = true
+	defer delete(allowedDownloadHosts, serverUrl.Hostname())
+
+	sha1Of := func(s string) string { return fmt.Sprintf("%x", sha1.Sum([]byte(s))) }
+	index := map[string]any{
+		"formatVersion": 1,
+		"game":          "minecraft",
+		"versionId":     "1.0.0",
+		"name":          "Test Pack",
+		"dependencies":  map[string]string{"minecraft": "1.20.4", "fabric-loader": "0.15.3"},
+		"files": []map[string]any{
+			{"path": "mods/sodium.jar", "hashes": map[string]string{"sha1": sha1Of("/sodium.jar")}, "downloads": []string{server.URL + "/sodium.jar"}},
+			{"path": "mods/server.jar", "hashes": map[string]string{"sha1": sha1Of("/server.jar")}, "downloads": []string{server.URL + "/server.jar"},
+				"env": map[string]string{"client": "unsupported", "server": "required"}},
+		},
+	}
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	for name, content := range map[string]string{
+		"overrides/options.txt":        "common",
+		"overrides/config/a.json":      "{}",
+		"client-overrides/options.txt": "client",
+	} {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	f, err := w.Create(packIndexFileName)
+	require.NoError(t, err)
+	require.NoError(t, json.NewEncoder(f).Encode(index))
+	require.NoError(t, w.Close())
+
+	r, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	require.NoError(t, err)
+	pack, err := ReadPack(r)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Pack", pack.Name)
+	assert.Equal(t, "0.15.3", pack.Dependencies[DependencyFabric])
+
+	dir := t.TempDir()
+	require.NoError(t, InstallPack(context.Background(), server.Client(), r, pack, dir))
+	assert.FileExists(t, filepath.Join(dir, "mods", "sodium.jar"))
+	assert.NoFileExists(t, filepath.Join(dir, "mods", "server.jar"))
+	assert.FileExists(t, filepath.Join(dir, "config", "a.json"))
+	options, err := os.ReadFile(filepath.Join(dir, "options.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "client", string(options))
+
+	// Hash mismatches and disallowed hosts fail
+	pack.Files[0].Hashes["sha1"] = sha1Of("other")
+	require.NoError(t, os.Remove(filepath.Join(dir, "mods", "sodium.jar")))
+	assert.Error(t, InstallPack(context.Background(), server.Client(), r, pack, dir))
+	pack.Files[0].Downloads = []string{"https://example.com/sodium.jar"}
+	assert.ErrorIs(t, InstallPack(context.Background(), server.Client(), r, pack, dir), ErrInvalidPack)
+}
+
+func TestWritePack(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"options.txt", "sodium.json", "server.properties"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	index := &PackIndex{Name: "Test", VersionID: "1.0.0", Files: []*PackFile{}, Dependencies: map[string]string{DependencyMinecraft: "1.20.4"}}
+	overrides := []*PackOverride{
+		{Path: "options.txt", File: filepath.Join(dir, "options.txt")},
+		{Path: "config/sodium.json", File: filepath.Join(dir, "sodium.json"), Env: &PackEnv{Client: Required, Server: Unsupported}},
+		{Path: "server.properties", File: filepath.Join(dir, "server.properties"), Env: &PackEnv{Client: Unsupported, Server: Required}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WritePack(&buf, index, overrides))
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	read, err := ReadPack(r)
+	require.NoError(t, err)
+	assert.Equal(t, "Test", read.Name)
+	assert.Equal(t, "1.20.4", read.Dependencies[DependencyMinecraft])
+
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{packIndexFileName, "overrides/options.txt", "client-overrides/config/sodium.json", "server-overrides/server.properties"}, names)
+}
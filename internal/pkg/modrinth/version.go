@@ -0,0 +1,97 @@
+package modrinth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrVersionNotFound = errors.New("version not found")
+
+type Version struct {
+	ID            string    `json:"id"`
+	ProjectID     string    `json:"project_id"`
+	Name          string    `json:"name"`
+	VersionNumber string    `json:"version_number"`
+	VersionType   string    `json:"version_type"` // release, beta or alpha
+	GameVersions  []string  `json:"game_versions"`
+	Loaders       []string  `json:"loaders"`
+	DatePublished time.Time `json:"date_published"`
+	Files         []File    `json:"files"`
+}
+
+type File struct {
+	Hashes   map[string]string `json:"hashes"` // sha1 and sha512
+	Url      string            `json:"url"`
+	Filename string            `json:"filename"`
+	Primary  bool              `json:"primary"`
+	Size     int64             `json:"size"`
+}
+
+// PrimaryFile returns the primary file of the version, or the first file if none is marked as primary.
+func (v *Version) PrimaryFile() *File {
+	for i := range v.Files {
+		if v.Files[i].Primary {
+			return &v.Files[i]
+		}
+	}
+	if len(v.Files) > 0 {
+		return &v.Files[0]
+	}
+	return nil
+}
+
+// GetProjectVersions returns every version of the project with the given id or slug, newest first.
+func (c *Client) GetProjectVersions(ctx context.Context, idOrSlug string) ([]*Version, error) {
+	versions, err := get[[]*Version](c, ctx, fmt.Sprintf("/project/%s/version", url.PathEscape(idOrSlug)), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	return *versions, nil
+}
+
+// FindProjectVersion returns the version of the project with the given id or version number. If version is
+// empty, the newest release is returned (or the newest version if there are no releases).
+func (c *Client) FindProjectVersion(ctx context.Context, idOrSlug, version string) (*Version, error) {
+	versions, err := c.GetProjectVersions(ctx, idOrSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if version == "" && v.VersionType == "release" {
+			return v, nil
+		}
+		if version != "" && (v.ID == version || strings.EqualFold(v.VersionNumber, version)) {
+			return v, nil
+		}
+	}
+	if version == "" && len(versions) > 0 {
+		return versions[0], nil
+	}
+	return nil, fmt.Errorf("%w: %s@%s", ErrVersionNotFound, idOrSlug, version)
+}
+
+// GetVersionsFromHashes returns the versions containing files with the given sha1 hashes, keyed by hash.
+// Hashes which are not known to Modrinth are not present in the result.
+func (c *Client) GetVersionsFromHashes(ctx context.Context, sha1Hashes []string) (map[string]*Version, error) {
+	body := map[string]any{"hashes": sha1Hashes, "algorithm": "sha1"}
+	versions, err := post[map[string]*Version](c, ctx, "/version_files", body)
+	if err != nil {
+		return nil, err
+	}
+	return *versions, nil
+}
+
+// FileWithHash returns the file of the version with the given sha1 hash, or nil.
+func (v *Version) FileWithHash(sha1Hash string) *File {
+	for i := range v.Files {
+		if v.Files[i].Hashes["sha1"] == sha1Hash {
+			return &v.Files[i]
+		}
+	}
+	return nil
+}
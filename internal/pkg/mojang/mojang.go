@@ -27,12 +27,12 @@ type Client struct {
 	timeout      time.Duration
 }
 
-func NewProfileClient(idVersion string, accountToken string) *Client {
+func NewProfileClient(idVersion string, httpClient *http.Client, accountToken string) *Client {
 	return &Client{
 		baseUrl:      profileApiUrl,
 		userAgent:    util.MakeUserAgent(idVersion),
 		accountToken: accountToken,
-		httpClient:   http.DefaultClient,
+		httpClient:   httpClient,
 		timeout:      10 * time.Second,
 	}
 }
@@ -0,0 +1,38 @@
+package network
+
+import (
+	"sync"
+	"time"
+)
+
+const maxChunkSize = 32 * 1024
+
+// limiter limits the combined rate of all response bodies to a number of bytes per second.
+type limiter struct {
+	rate int64
+
+	mu   sync.Mutex
+	next time.Time // The time at which all bytes read so far are within the limit
+}
+
+func newLimiter(rate int64) *limiter {
+	return &limiter{rate: rate}
+}
+
+// wait blocks until reading n more bytes would be within the limit.
+func (l *limiter) wait(n int) {
+	if n <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.rate))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	time.Sleep(delay)
+}
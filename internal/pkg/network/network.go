@@ -0,0 +1,124 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/mworzala/mc/internal/pkg/config"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const defaultTimeout = 30 * time.Second
+
+var ErrInvalidCaBundle = errors.New("no certificates found in ca bundle")
+
+// NewClient creates the http client used for all network access, configured from the network options.
+//
+// Every request is sent with the given user agent. If offline is set, every request fails with util.ErrOffline.
+func NewClient(opts config.NetworkOpts, userAgent string, offline bool) (*http.Client, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	// Proxy, if not set the standard environment variables are used
+	if opts.Proxy != "" {
+		proxyUrl, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy: %w", err)
+		}
+		base.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	// Custom CA bundle, in addition to the system certificates
+	if opts.CaBundle != "" {
+		pem, err := os.ReadFile(opts.CaBundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCaBundle, opts.CaBundle)
+		}
+		base.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	// The timeout only applies until the response starts, a full request timeout would break large downloads
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	base.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	base.TLSHandshakeTimeout = timeout
+	base.ResponseHeaderTimeout = timeout
+
+	t := &transport{
+		base:      base,
+		userAgent: userAgent,
+		debug:     opts.Debug,
+		offline:   offline,
+	}
+	if opts.BandwidthLimit > 0 {
+		t.limiter = newLimiter(opts.BandwidthLimit)
+	}
+	return &http.Client{Transport: t}, nil
+}
+
+type transport struct {
+	base      http.RoundTripper
+	userAgent string
+	debug     bool
+	offline   bool
+	limiter   *limiter
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.offline {
+		return nil, fmt.Errorf("%w: %s", util.ErrOffline, req.URL)
+	}
+
+	// A RoundTripper must not modify the original request
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	if t.debug {
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "http %s %s: %s\n", req.Method, req.URL, err)
+		} else {
+			_, _ = fmt.Fprintf(os.Stderr, "http %s %s: %d (%s)\n", req.Method, req.URL, res.StatusCode, time.Since(start).Round(time.Millisecond))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if t.limiter != nil {
+		res.Body = &limitedBody{ReadCloser: res.Body, limiter: t.limiter}
+	}
+	return res, nil
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	limiter *limiter
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	// Keep reads small so that the rate is smooth
+	if len(p) > maxChunkSize {
+		p = p[:maxChunkSize]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.limiter.wait(n)
+	return n, err
+}
@@ -0,0 +1,70 @@
+package network
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mworzala/mc/internal/pkg/config"
+	"github.com/mworzala/mc/internal/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.UserAgent())
+	}))
+	defer server.Close()
+
+	client, err := NewClient(config.NetworkOpts{}, "mworzala/mc/test", false)
+	require.NoError(t, err)
+
+	res, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "mworzala/mc/test", string(body))
+}
+
+func TestClientOffline(t *testing.T) {
+	client, err := NewClient(config.NetworkOpts{}, "mworzala/mc/test", true)
+	require.NoError(t, err)
+
+	_, err = client.Get("http://localhost")
+	assert.ErrorIs(t, err, util.ErrOffline)
+}
+
+func TestClientBandwidthLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("a", 2000))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(config.NetworkOpts{BandwidthLimit: 10_000}, "mworzala/mc/test", false)
+	require.NoError(t, err)
+
+	start := time.Now()
+	res, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	_, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	// 2000 bytes at 10000 bytes per second
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestClientInvalidCaBundle(t *testing.T) {
+	bundle := path.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(bundle, []byte("not a certificate"), 0644))
+
+	_, err := NewClient(config.NetworkOpts{CaBundle: bundle}, "mworzala/mc/test", false)
+	assert.ErrorIs(t, err, ErrInvalidCaBundle)
+}
@@ -45,6 +45,26 @@ func GetConfigDir(indev bool) (dataDir string, err error) {
 	return dataDir, nil
 }
 
+// MinecraftDir returns the default directory of the vanilla Minecraft launcher (.minecraft) for the host platform.
+func MinecraftDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return path.Join(os.Getenv("APPDATA"), ".minecraft"), nil
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return path.Join(home, "Library", "Application Support", "minecraft"), nil
+	default:
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return path.Join(home, ".minecraft"), nil
+	}
+}
+
 // OpenUrl opens the given URL in the system default browser, or returns an error.
 // Credit: https://gist.github.com/hyg/9c4afcd91fe24316cbf0
 func OpenUrl(url string) error {
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package platform
+
+import (
+	"errors"
+	"syscall"
+)
+
+// IsProcessRunning returns true if a process with the given pid exists.
+func IsProcessRunning(pid int) bool {
+	err := syscall.Kill(pid, syscall.Signal(0))
+	// EPERM means the process exists but is owned by someone else
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package platform
+
+import "golang.org/x/sys/windows"
+
+const stillActive = 259
+
+// IsProcessRunning returns true if a process with the given pid exists.
+func IsProcessRunning(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
@@ -0,0 +1,194 @@
+// Package prism reads MultiMC and Prism Launcher instances.
+package prism
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const (
+	instanceCfgFileName = "instance.cfg"
+	packFileName        = "mmc-pack.json"
+
+	// Component uids in mmc-pack.json
+	minecraftUid = "net.minecraft"
+	fabricUid    = "net.fabricmc.fabric-loader"
+	quiltUid     = "org.quiltmc.quilt-loader"
+	forgeUid     = "net.minecraftforge"
+	neoForgeUid  = "net.neoforged"
+)
+
+var (
+	ErrNotInstance        = errors.New("not a MultiMC/Prism instance")
+	ErrUnsupportedVersion = errors.New("unsupported instance version")
+)
+
+// Instance is the subset of a MultiMC/Prism instance which can be used by a profile.
+type Instance struct {
+	// Dir is the instance directory, GameDir is the .minecraft directory inside of it
+	Dir     string
+	GameDir string
+	Name    string
+
+	GameVersion string
+	Type        profile.Type
+	Loader      string
+
+	// Java settings, only set if overridden by the instance
+	JvmArgs   []string
+	MinMemory int
+	MaxMemory int
+}
+
+type mmcPack struct {
+	Components []struct {
+		Uid     string `json:"uid"`
+		Version string `json:"version"`
+	} `json:"components"`
+}
+
+// ReadInstance reads the instance in the given directory.
+func ReadInstance(dir string) (*Instance, error) {
+	cfg, err := readCfg(filepath.Join(dir, instanceCfgFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotInstance, dir)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var pack mmcPack
+	packData, err := os.ReadFile(filepath.Join(dir, packFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", packFileName, err)
+	}
+	if err := json.Unmarshal(packData, &pack); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", packFileName, err)
+	}
+
+	instance := &Instance{Dir: dir, Name: cfg["name"], Type: profile.Vanilla}
+	if instance.Name == "" {
+		instance.Name = filepath.Base(dir)
+	}
+	for _, component := range pack.Components {
+		switch component.Uid {
+		case minecraftUid:
+			instance.GameVersion = component.Version
+		case fabricUid:
+			instance.Type, instance.Loader = profile.Fabric, component.Version
+		case quiltUid:
+			instance.Type, instance.Loader = profile.Quilt, component.Version
+		case forgeUid:
+			instance.Type, instance.Loader = profile.Forge, component.Version
+		case neoForgeUid:
+			instance.Type, instance.Loader = profile.NeoForge, component.Version
+		}
+	}
+	if instance.GameVersion == "" {
+		return nil, fmt.Errorf("%w: no minecraft version in %s", ErrUnsupportedVersion, packFileName)
+	}
+
+	// Newer Prism versions use minecraft rather than .minecraft
+	instance.GameDir = filepath.Join(dir, ".minecraft")
+	if _, err := os.Stat(instance.GameDir); err != nil {
+		if _, err := os.Stat(filepath.Join(dir, "minecraft")); err == nil {
+			instance.GameDir = filepath.Join(dir, "minecraft")
+		}
+	}
+
+	if cfg["OverrideJavaArgs"] == "true" {
+		instance.JvmArgs = util.SplitArgs(cfg["JvmArgs"])
+	}
+	if cfg["OverrideMemory"] == "true" {
+		instance.MinMemory, _ = strconv.Atoi(cfg["MinMemAlloc"])
+		instance.MaxMemory, _ = strconv.Atoi(cfg["MaxMemAlloc"])
+	}
+
+	return instance, nil
+}
+
+// FindInstances returns the instance directories of every known Prism Launcher (or MultiMC) install.
+func FindInstances() (result []string) {
+	for _, dataDir := range dataDirs() {
+		entries, err := os.ReadDir(filepath.Join(dataDir, "instances"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			dir := filepath.Join(dataDir, "instances", entry.Name())
+			if _, err := os.Stat(filepath.Join(dir, instanceCfgFileName)); entry.IsDir() && err == nil {
+				result = append(result, dir)
+			}
+		}
+	}
+	return
+}
+
+// dataDirs returns the default Prism Launcher data directories for the host platform. MultiMC is portable
+// so has no default location, its instances must be imported by path.
+func dataDirs() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		return []string{
+			filepath.Join(dataHome, "PrismLauncher"),
+			filepath.Join(home, ".var", "app", "org.prismlauncher.PrismLauncher", "data", "PrismLauncher"),
+		}
+	case "darwin":
+		return []string{filepath.Join(home, "Library", "Application Support", "PrismLauncher")}
+	case "windows":
+		return []string{filepath.Join(os.Getenv("APPDATA"), "PrismLauncher")}
+	}
+	return nil
+}
+
+// readCfg reads the key value pairs of an instance.cfg file (an ini file written by QSettings).
+// Sections are ignored, as instance settings are all in the General section.
+func readCfg(file string) (map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		result[strings.TrimSpace(key)] = unquoteCfgValue(strings.TrimSpace(value))
+	}
+	return result, scanner.Err()
+}
+
+// unquoteCfgValue removes the quotes QSettings adds around values containing special characters.
+func unquoteCfgValue(value string) string {
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return value
+	}
+	value = value[1 : len(value)-1]
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(value)
+}
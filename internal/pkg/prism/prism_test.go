@@ -0,0 +1,46 @@
+package prism
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mworzala/mc/internal/pkg/profile"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadInstance(t *testing.T) {
+	dir := t.TempDir()
+	cfg := `[General]
+ConfigVersion=1.2
+name=Fabulously Optimized
+OverrideJavaArgs=true
+JvmArgs="-XX:+UseG1GC -Dfoo=\"a b\""
+OverrideMemory=true
+MinMemAlloc=512
+MaxMemAlloc=4096
+`
+	pack := `{"components": [
+		{"uid": "org.lwjgl3", "version": "3.3.2"},
+		{"uid": "net.minecraft", "version": "1.20.4"},
+		{"uid": "net.fabricmc.intermediary", "version": "1.20.4"},
+		{"uid": "net.fabricmc.fabric-loader", "version": "0.15.3"}
+	], "formatVersion": 1}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, instanceCfgFileName), []byte(cfg), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, packFileName), []byte(pack), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "minecraft"), 0755))
+
+	instance, err := ReadInstance(dir)
+	require.NoError(t, err)
+	require.Equal(t, "Fabulously Optimized", instance.Name)
+	require.Equal(t, filepath.Join(dir, "minecraft"), instance.GameDir)
+	require.Equal(t, "1.20.4", instance.GameVersion)
+	require.Equal(t, profile.Fabric, instance.Type)
+	require.Equal(t, "0.15.3", instance.Loader)
+	require.Equal(t, []string{"-XX:+UseG1GC", "-Dfoo=a b"}, instance.JvmArgs)
+	require.Equal(t, 512, instance.MinMemory)
+	require.Equal(t, 4096, instance.MaxMemory)
+
+	_, err = ReadInstance(t.TempDir())
+	require.ErrorIs(t, err, ErrNotInstance)
+}
@@ -0,0 +1,170 @@
+package profile
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/util"
+)
+
+const (
+	archiveMetadataFile  = "profile.json"
+	archiveFilesDir      = "files/"
+	archiveFormatVersion = 1
+	// The metadata may contain a custom version spec, which is usually well under this limit
+	archiveMaxMetadataSize = 16 << 20
+)
+
+var (
+	ErrInvalidArchive = errors.New("invalid profile archive")
+
+	// DefaultExportEntries are the game directory entries exported if none are given
+	DefaultExportEntries = []string{"config", "mods", "resourcepacks", "shaderpacks", "options.txt", "servers.dat"}
+)
+
+// ArchiveMetadata describes the profile in an exported archive. The version itself is not included, it is
+// installed again on import. Only custom versions include their spec, because they cannot be resolved.
+type ArchiveMetadata struct {
+	FormatVersion int    `json:"format_version"`
+	Name          string `json:"name"`
+	Type          Type   `json:"type"`
+	// Version is the id of the installed version, GameVersion is the Minecraft version it is based on
+	Version     string          `json:"version"`
+	GameVersion string          `json:"game_version"`
+	Loader      string          `json:"loader,omitempty"`
+	Config      json.RawMessage `json:"config,omitempty"`
+	Spec        json.RawMessage `json:"spec,omitempty"`
+}
+
+// ExportArchive writes a zip archive containing the metadata and the given top level entries of the profile
+// game directory (eg mods or saves). Entries which do not exist are skipped.
+func ExportArchive(w io.Writer, p *Profile, metadata *ArchiveMetadata, entries []string) error {
+	metadata.FormatVersion = archiveFormatVersion
+
+	// The profile config is part of the metadata, so that it is restored even if not selected. The java
+	// installation is left out, it names an installation on this machine.
+	if _, err := os.Stat(p.configFile()); err == nil {
+		config := *p.Config()
+		config.Java = ""
+		if metadata.Config, err = json.Marshal(&config); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	mw, err := zw.Create(archiveMetadataFile)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(mw).Encode(metadata); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry == configFileName {
+			continue
+		}
+		source, err := util.SafeJoin(p.Directory, entry)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(source); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := addToZip(zw, source, archiveFilesDir+filepath.ToSlash(filepath.Clean(entry))); err != nil {
+			return fmt.Errorf("failed to export %s: %w", entry, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// addToZip adds the file or directory at source to the archive, recursively.
+func addToZip(zw *zip.Writer, source, name string) error {
+	return filepath.WalkDir(source, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, file)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		header.Method = zip.Deflate
+
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	})
+}
+
+// ReadArchiveMetadata reads the metadata of an archive written by ExportArchive.
+func ReadArchiveMetadata(r *zip.Reader) (*ArchiveMetadata, error) {
+	f, err := r.Open(archiveMetadataFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing %s", ErrInvalidArchive, archiveMetadataFile)
+	}
+	defer f.Close()
+
+	var metadata ArchiveMetadata
+	if err := json.NewDecoder(io.LimitReader(f, archiveMaxMetadataSize)).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidArchive, err)
+	}
+	if metadata.FormatVersion < 1 || metadata.FormatVersion > archiveFormatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArchive, metadata.FormatVersion)
+	}
+	if metadata.Version == "" {
+		return nil, fmt.Errorf("%w: missing version", ErrInvalidArchive)
+	}
+	return &metadata, nil
+}
+
+// ImportArchiveFiles restores the game directory content and config of an archive into the profile.
+func ImportArchiveFiles(r *zip.Reader, p *Profile, metadata *ArchiveMetadata) error {
+	if err := util.ExtractZip(r, archiveFilesDir, p.Directory, false); err != nil {
+		return err
+	}
+
+	if len(metadata.Config) > 0 {
+		if err := os.MkdirAll(path.Dir(p.configFile()), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(p.configFile(), metadata.Config, 0644); err != nil {
+			return err
+		}
+		p.config = nil
+	}
+	return nil
+}
+
+// IsArchiveEntry returns true if the given export entry is a plain top level name, eg mods or options.txt.
+func IsArchiveEntry(entry string) bool {
+	return entry != "" && filepath.IsLocal(entry) && !strings.ContainsAny(entry, `/\`)
+}
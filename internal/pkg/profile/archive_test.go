@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	source := &Profile{Name: "source", Directory: t.TempDir()}
+	files := map[string]string{
+		"config.json":          `{"java":"temurin-21","max_memory":4096}`,
+		"options.txt":          "fov:0.5",
+		"mods/sodium.jar":      "sodium",
+		"config/sodium/a.json": "{}",
+		"saves/world/level":    "level",
+	}
+	for name, content := range files {
+		file := filepath.Join(source.Directory, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+	}
+
+	var buf bytes.Buffer
+	metadata := &ArchiveMetadata{Name: "source", Type: Fabric, Version: "fabric-loader-0.15.0-1.20.4", GameVersion: "1.20.4", Loader: "0.15.0"}
+	require.NoError(t, ExportArchive(&buf, source, metadata, []string{"mods", "config", "options.txt", "servers.dat"}))
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	read, err := ReadArchiveMetadata(r)
+	require.NoError(t, err)
+	require.Equal(t, Fabric, read.Type)
+	require.Equal(t, "1.20.4", read.GameVersion)
+	require.JSONEq(t, `{"max_memory":4096}`, string(read.Config))
+
+	target := &Profile{Name: "target", Directory: t.TempDir()}
+	require.NoError(t, ImportArchiveFiles(r, target, read))
+	for _, name := range []string{"options.txt", "mods/sodium.jar", "config/sodium/a.json"} {
+		content, err := os.ReadFile(filepath.Join(target.Directory, filepath.FromSlash(name)))
+		require.NoError(t, err, name)
+		require.Equal(t, files[name], string(content))
+	}
+	require.NoDirExists(t, filepath.Join(target.Directory, "saves"))
+	require.Equal(t, "", target.Config().Java)
+	require.Equal(t, 4096, target.Config().MaxMemory)
+}
+
+func TestIsArchiveEntry(t *testing.T) {
+	require.True(t, IsArchiveEntry("mods"))
+	require.True(t, IsArchiveEntry("options.txt"))
+	require.False(t, IsArchiveEntry(""))
+	require.False(t, IsArchiveEntry("../mods"))
+	require.False(t, IsArchiveEntry("config/sodium"))
+	require.False(t, IsArchiveEntry("/etc"))
+}
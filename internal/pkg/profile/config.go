@@ -3,5 +3,11 @@ package profile
 // Config represents all the profile specific configuration options.
 // Options not specified in a profile config will be inherited from the global config.
 type Config struct {
-	Java string `mapstructure:"java"` // The name of the java installation to use
+	Java string `mapstructure:"java" json:"java,omitempty"` // The name of the java installation to use
+
+	// JvmArgs are passed to java after the jvm arguments from the version spec, so they take precedence
+	JvmArgs []string `mapstructure:"jvm_args" json:"jvm_args,omitempty"`
+	// MinMemory and MaxMemory set the java heap size (-Xms and -Xmx) in megabytes, if not zero
+	MinMemory int `mapstructure:"min_memory" json:"min_memory,omitempty"`
+	MaxMemory int `mapstructure:"max_memory" json:"max_memory,omitempty"`
 }
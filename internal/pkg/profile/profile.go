@@ -9,6 +9,8 @@ import (
 	"path"
 	"regexp"
 	"strings"
+
+	"github.com/mworzala/mc/internal/pkg/util"
 )
 
 var (
@@ -16,7 +18,8 @@ var (
 	ErrNameInUse   = errors.New("name in use")
 	ErrNotFound    = errors.New("profile not found")
 
-	namePattern = regexp.MustCompile("^[a-zA-Z0-9_.-]{1,32}$")
+	namePattern      = regexp.MustCompile("^[a-zA-Z0-9_.-]{1,32}$")
+	invalidNameChars = regexp.MustCompile("[^a-zA-Z0-9_.-]+")
 )
 
 func IsValidName(name string) bool {
@@ -24,6 +27,18 @@ func IsValidName(name string) bool {
 	return namePattern.MatchString(name)
 }
 
+// ToValidName converts a name which may contain any characters (eg a modpack title) to a valid profile name.
+func ToValidName(name string) string {
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "-"), "-")
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if name == "" {
+		name = "profile"
+	}
+	return name
+}
+
 type Manager interface {
 	// CreateProfile creates a new profile and fills in defaults.
 	// The returned profile may be modified, and then Save will save it.
@@ -32,6 +47,22 @@ type Manager interface {
 	Profiles() []string
 	GetProfile(name string) (*Profile, error)
 
+	// DeleteProfile removes the profile with the given name. If deleteFiles is set, the game
+	// directory is deleted as well, but only if it is the directory created by CreateProfile.
+	//
+	// The Manager is not responsible for persisting the change, Save should be called afterwards.
+	DeleteProfile(name string, deleteFiles bool) error
+	// RenameProfile changes the name of a profile. If moveFiles is set and the game directory is
+	// the one created by CreateProfile, it is moved to match the new name.
+	//
+	// The Manager is not responsible for persisting the change, Save should be called afterwards.
+	RenameProfile(name, newName string, moveFiles bool) (*Profile, error)
+	// CloneProfile creates a new profile with the same type and version as an existing one.
+	// If copyFiles is set the game directory contents are copied, otherwise only the profile config.
+	//
+	// The Manager is not responsible for persisting the change, Save should be called afterwards.
+	CloneProfile(name, newName string, copyFiles bool) (*Profile, error)
+
 	Save() error
 }
 
@@ -69,6 +100,9 @@ func NewManager(dataDir string) (Manager, error) {
 	if manager.AllProfiles == nil {
 		manager.AllProfiles = make(map[string]*Profile)
 	}
+	for _, p := range manager.AllProfiles {
+		p.dataDir = path.Join(manager.profilesDir, p.Name)
+	}
 	return &manager, nil
 }
 
@@ -89,6 +123,7 @@ func (m *fileManager) CreateProfile(name string) (*Profile, error) {
 		Name:      name,
 		Type:      Unknown,
 		Directory: dataDir,
+		dataDir:   dataDir,
 	}
 
 	m.AllProfiles[strings.ToLower(name)] = profile
@@ -112,6 +147,119 @@ func (m *fileManager) GetProfile(name string) (*Profile, error) {
 	return nil, ErrNotFound
 }
 
+func (m *fileManager) DeleteProfile(name string, deleteFiles bool) error {
+	p, err := m.GetProfile(name)
+	if err != nil {
+		return err
+	}
+
+	if deleteFiles && m.isManagedDir(p) {
+		if err := os.RemoveAll(p.Directory); err != nil {
+			return fmt.Errorf("failed to delete profile data directory: %w", err)
+		}
+	} else if !m.isManagedDir(p) {
+		// Only the config is kept in the data directory of a profile using an external directory
+		if err := os.Remove(p.configFile()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete profile config: %w", err)
+		}
+		_ = os.Remove(p.dataDir) // Only if empty
+	}
+
+	delete(m.AllProfiles, strings.ToLower(name))
+	if m.Default == strings.ToLower(name) {
+		m.Default = ""
+	}
+	return nil
+}
+
+func (m *fileManager) RenameProfile(name, newName string, moveFiles bool) (*Profile, error) {
+	p, err := m.GetProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	if !IsValidName(newName) {
+		return nil, ErrInvalidName
+	}
+	// A change in case only is still the same profile
+	if _, ok := m.AllProfiles[strings.ToLower(newName)]; ok && !strings.EqualFold(name, newName) {
+		return nil, ErrNameInUse
+	}
+
+	newDataDir := path.Join(m.profilesDir, newName)
+	if moveFiles && m.isManagedDir(p) {
+		if err := os.Rename(p.Directory, newDataDir); err != nil {
+			return nil, fmt.Errorf("failed to move profile data directory: %w", err)
+		}
+		p.Directory = newDataDir
+	} else if p.dataDir != "" && p.dataDir != newDataDir {
+		// The game directory stays, but the config follows the profile name
+		if err := moveConfig(p.configFile(), path.Join(newDataDir, configFileName)); err != nil {
+			return nil, fmt.Errorf("failed to move profile config: %w", err)
+		}
+		_ = os.Remove(p.dataDir) // Only if empty
+	}
+	p.dataDir = newDataDir
+	p.config = nil
+
+	delete(m.AllProfiles, strings.ToLower(name))
+	p.Name = newName
+	m.AllProfiles[strings.ToLower(newName)] = p
+	if m.Default == strings.ToLower(name) {
+		m.Default = strings.ToLower(newName)
+	}
+	return p, nil
+}
+
+func (m *fileManager) CloneProfile(name, newName string, copyFiles bool) (*Profile, error) {
+	source, err := m.GetProfile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := m.CreateProfile(newName)
+	if err != nil {
+		return nil, err
+	}
+	p.Type = source.Type
+	p.Version = source.Version
+	p.Loader = source.Loader
+
+	if copyFiles {
+		err = util.CopyDir(source.Directory, p.Directory)
+	}
+	if err == nil {
+		// The config of a profile using an external directory is not part of its game directory
+		err = util.CopyFile(source.configFile(), p.configFile())
+		if errors.Is(err, fs.ErrNotExist) {
+			err = nil // No config to copy
+		}
+	}
+	if err != nil {
+		delete(m.AllProfiles, strings.ToLower(newName))
+		_ = os.RemoveAll(p.Directory)
+		return nil, fmt.Errorf("failed to copy profile data: %w", err)
+	}
+
+	return p, nil
+}
+
+// moveConfig moves a profile config to a new data directory, if it exists.
+func moveConfig(from, to string) error {
+	if _, err := os.Stat(from); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := os.MkdirAll(path.Dir(to), 0755); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+// isManagedDir returns true if the profile directory is the default one created by CreateProfile,
+// rather than a directory elsewhere which the profile was pointed to.
+func (m *fileManager) isManagedDir(p *Profile) bool {
+	return path.Clean(p.Directory) == path.Join(m.profilesDir, p.Name)
+}
+
 func (m *fileManager) Save() error {
 	f, err := os.OpenFile(m.Path, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
 	if err != nil {
@@ -0,0 +1,156 @@
+package profile
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestManager(t *testing.T) *fileManager {
+	dataDir := t.TempDir()
+	m, err := NewManager(dataDir)
+	require.NoError(t, err)
+	return m.(*fileManager)
+}
+
+func TestDeleteProfile(t *testing.T) {
+	m := newTestManager(t)
+
+	managed, err := m.CreateProfile("managed")
+	require.NoError(t, err)
+	external, err := m.CreateProfile("external")
+	require.NoError(t, err)
+	require.NoError(t, os.RemoveAll(external.Directory))
+	external.Directory = t.TempDir()
+	m.Default = "managed"
+
+	// Files are kept unless requested
+	require.NoError(t, m.DeleteProfile("Managed", false))
+	assert.DirExists(t, managed.Directory)
+	assert.Equal(t, "", m.Default)
+	_, err = m.GetProfile("managed")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// External directories are never deleted
+	require.NoError(t, m.DeleteProfile("external", true))
+	assert.DirExists(t, external.Directory)
+
+	managed, err = m.CreateProfile("managed")
+	require.NoError(t, err)
+	require.NoError(t, m.DeleteProfile("managed", true))
+	assert.NoDirExists(t, managed.Directory)
+
+	assert.ErrorIs(t, m.DeleteProfile("missing", true), ErrNotFound)
+}
+
+func TestRenameProfile(t *testing.T) {
+	m := newTestManager(t)
+
+	p, err := m.CreateProfile("old")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(p.Directory, "options.txt"), []byte("options"), 0644))
+	_, err = m.CreateProfile("other")
+	require.NoError(t, err)
+	m.Default = "old"
+
+	_, err = m.RenameProfile("old", "Other", true)
+	assert.ErrorIs(t, err, ErrNameInUse)
+	_, err = m.RenameProfile("old", "not valid", true)
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	// Managed directories are moved along with the profile
+	oldDir := p.Directory
+	p, err = m.RenameProfile("old", "new", true)
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(m.profilesDir, "new"), p.Directory)
+	assert.FileExists(t, path.Join(p.Directory, "options.txt"))
+	assert.NoDirExists(t, oldDir)
+	assert.Equal(t, "new", m.Default)
+
+	// A change in case only is allowed
+	p, err = m.RenameProfile("new", "New", true)
+	require.NoError(t, err)
+	assert.Equal(t, "New", p.Name)
+	assert.FileExists(t, path.Join(p.Directory, "options.txt"))
+	assert.Equal(t, "new", m.Default)
+
+	// External directories are never moved
+	external := t.TempDir()
+	p.Directory = external
+	p, err = m.RenameProfile("new", "renamed", true)
+	require.NoError(t, err)
+	assert.Equal(t, external, p.Directory)
+	assert.DirExists(t, external)
+}
+
+func TestCloneProfile(t *testing.T) {
+	m := newTestManager(t)
+
+	source, err := m.CreateProfile("source")
+	require.NoError(t, err)
+	source.Type, source.Version, source.Loader = Fabric, "fabric-loader-0.15.0-1.20.4", "0.15.0"
+	require.NoError(t, os.WriteFile(path.Join(source.Directory, configFileName), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(path.Join(source.Directory, "options.txt"), []byte("options"), 0644))
+
+	// Only the config is copied by default
+	p, err := m.CloneProfile("source", "config-only", false)
+	require.NoError(t, err)
+	assert.Equal(t, source.Version, p.Version)
+	assert.Equal(t, source.Loader, p.Loader)
+	assert.FileExists(t, path.Join(p.Directory, configFileName))
+	assert.NoFileExists(t, path.Join(p.Directory, "options.txt"))
+
+	p, err = m.CloneProfile("source", "full", true)
+	require.NoError(t, err)
+	assert.FileExists(t, path.Join(p.Directory, "options.txt"))
+
+	_, err = m.CloneProfile("source", "full", true)
+	assert.ErrorIs(t, err, ErrNameInUse)
+	assert.DirExists(t, p.Directory, "existing profile must not be removed")
+
+	// A failed copy leaves nothing behind
+	require.NoError(t, os.RemoveAll(source.Directory))
+	_, err = m.CloneProfile("source", "failed", true)
+	assert.Error(t, err)
+	_, err = m.GetProfile("failed")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoDirExists(t, path.Join(m.profilesDir, "failed"))
+}
+
+func TestSharedDirectory(t *testing.T) {
+	m := newTestManager(t)
+	shared := t.TempDir()
+
+	// Profiles imported from launcher installations using the same game directory
+	for name, memory := range map[string]int{"first": 1024, "second": 4096} {
+		p, err := m.CreateProfile(name)
+		require.NoError(t, err)
+		require.NoError(t, os.Remove(p.Directory))
+		p.Directory = shared
+		p.Config().MaxMemory = memory
+		require.NoError(t, p.SaveConfig())
+	}
+	require.NoError(t, m.Save())
+	assert.NoFileExists(t, path.Join(shared, configFileName))
+
+	reloaded, err := NewManager(path.Dir(m.profilesDir))
+	require.NoError(t, err)
+	for name, memory := range map[string]int{"first": 1024, "second": 4096} {
+		p, err := reloaded.GetProfile(name)
+		require.NoError(t, err)
+		assert.Equal(t, shared, p.Directory)
+		assert.Equal(t, memory, p.Config().MaxMemory)
+	}
+
+	// The config follows the profile, the shared directory is left alone
+	p, err := reloaded.RenameProfile("first", "renamed", true)
+	require.NoError(t, err)
+	assert.Equal(t, 1024, p.Config().MaxMemory)
+	assert.NoDirExists(t, path.Join(m.profilesDir, "first"))
+	require.NoError(t, reloaded.DeleteProfile("renamed", true))
+	assert.NoDirExists(t, path.Join(m.profilesDir, "renamed"))
+	assert.DirExists(t, shared)
+}
@@ -1,6 +1,8 @@
 package profile
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path"
 
@@ -14,17 +16,29 @@ const (
 	Unknown Type = iota
 	Vanilla
 	Fabric
+	Forge
+	NeoForge
+	Quilt
+	// Custom indicates a profile installed from a user provided version spec
+	Custom
 )
 
+const configFileName = "config.json"
+
 type Profile struct {
 	Name      string  `json:"name"`
 	Directory string  `json:"directory"`
 	config    *Config // Config is loaded on demand
+	// dataDir is the directory created for the profile by the Manager, which holds the config. It is the
+	// game directory unless the profile uses a directory shared with another launcher.
+	dataDir string
 
 	Type Type `json:"type"`
 	// Version represents the Minecraft version of the profile.
 	// Present no matter the type (except Unknown)
 	Version string `json:"version"`
+	// Loader is the mod loader version of the profile, or empty for vanilla profiles
+	Loader string `json:"loader,omitempty"`
 }
 
 func (p *Profile) Config() *Config {
@@ -33,7 +47,7 @@ func (p *Profile) Config() *Config {
 	}
 
 	v := viper.New()
-	v.SetConfigFile(path.Join(p.Directory, "config.json"))
+	v.SetConfigFile(p.configFile())
 
 	// Read the config file if it exists
 	if _, err := os.Stat(v.ConfigFileUsed()); err == nil {
@@ -50,3 +64,29 @@ func (p *Profile) Config() *Config {
 	p.config = &config
 	return p.config
 }
+
+// SaveConfig writes the profile config (as returned by Config) to the profile data directory.
+func (p *Profile) SaveConfig() error {
+	if err := os.MkdirAll(path.Dir(p.configFile()), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p.configFile(), os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", configFileName, err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(p.Config()); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
+
+// configFile returns the path of the profile config. It is kept out of game directories which are not owned by
+// the profile, so that other launchers (or other profiles using the same directory) are not affected.
+func (p *Profile) configFile() string {
+	if p.dataDir == "" {
+		return path.Join(p.Directory, configFileName)
+	}
+	return path.Join(p.dataDir, configFileName)
+}
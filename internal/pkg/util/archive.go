@@ -0,0 +1,195 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrUnsafePath = errors.New("unsafe path in archive")
+
+// ReadZipFile returns the content of a single file in a zip archive.
+func ReadZipFile(r *zip.Reader, name string) ([]byte, error) {
+	f, err := r.Open(strings.TrimPrefix(name, "/"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// ExtractZip extracts every file in the archive below prefix into dest, with the prefix removed.
+// Files which already exist in dest are overwritten unless keepExisting is set.
+func ExtractZip(r *zip.Reader, prefix, dest string, keepExisting bool) error {
+	for _, entry := range r.File {
+		name, ok := strings.CutPrefix(entry.Name, prefix)
+		if !ok || name == "" || entry.FileInfo().IsDir() {
+			continue
+		}
+
+		target, err := SafeJoin(dest, name)
+		if err != nil {
+			return err
+		}
+		if keepExisting {
+			if _, err := os.Stat(target); err == nil {
+				continue
+			}
+		}
+
+		if err := extractZipEntry(entry, target); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
+
+func extractZipEntry(entry *zip.File, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	in, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	mode := entry.Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// ExtractTarGz extracts every file, directory and symlink in the gzip compressed tar archive into dest.
+func ExtractTarGz(r io.Reader, dest string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		target, err := SafeJoin(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		// Links created by earlier entries must not redirect later entries outside of dest
+		if err := checkNoSymlinks(dest, target); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = extractTarEntry(tr, header, target)
+		case tar.TypeSymlink:
+			if !isLocalLink(dest, target, header.Linkname) {
+				return fmt.Errorf("%w: %s -> %s", ErrUnsafePath, header.Name, header.Linkname)
+			}
+			if err = os.MkdirAll(filepath.Dir(target), 0755); err == nil {
+				err = os.Symlink(header.Linkname, target)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+		}
+	}
+}
+
+// isLocalLink returns true if a symlink at target pointing to linkname resolves to a path inside dest.
+// Links may only go up before going down, as going up from another symlink (eg a/.. with a -> .) does not
+// resolve to the lexical parent. The directories above target are never symlinks, see checkNoSymlinks.
+func isLocalLink(dest, target, linkname string) bool {
+	if filepath.IsAbs(linkname) {
+		return false
+	}
+	down := false
+	for _, part := range strings.Split(filepath.FromSlash(linkname), string(filepath.Separator)) {
+		if part == ".." && down {
+			return false
+		}
+		down = down || (part != ".." && part != "." && part != "")
+	}
+	rel, err := filepath.Rel(dest, filepath.Join(filepath.Dir(target), linkname))
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// checkNoSymlinks returns an error if target, or any directory between dest and target, is a symlink.
+func checkNoSymlinks(dest, target string) error {
+	rel, err := filepath.Rel(dest, target)
+	if err != nil {
+		return err
+	}
+	current := dest
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s is a symlink", ErrUnsafePath, filepath.ToSlash(rel))
+		}
+	}
+	return nil
+}
+
+func extractTarEntry(r io.Reader, header *tar.Header, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	mode := header.FileInfo().Mode().Perm()
+	if mode == 0 {
+		mode = 0644
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, r); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// SafeJoin joins an archive (slash separated) path to dest, returning an error if the
+// result would be outside of dest.
+func SafeJoin(dest, name string) (string, error) {
+	name = filepath.FromSlash(name)
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	return filepath.Join(dest, name), nil
+}
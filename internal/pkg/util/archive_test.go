@@ -0,0 +1,70 @@
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTarGz(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []*tar.Header
+		wantErr bool
+	}{
+		{"local symlink", []*tar.Header{
+			{Name: "jdk/bin/java", Typeflag: tar.TypeReg},
+			{Name: "jdk/java", Typeflag: tar.TypeSymlink, Linkname: "bin/java"},
+		}, false},
+		{"absolute symlink", []*tar.Header{
+			{Name: "jdk/lib", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+		}, true},
+		{"relative symlink outside dest", []*tar.Header{
+			{Name: "jdk/lib", Typeflag: tar.TypeSymlink, Linkname: "../../.."},
+			{Name: "jdk/lib/x", Typeflag: tar.TypeReg},
+		}, true},
+		{"write through symlink", []*tar.Header{
+			{Name: "jdk/up", Typeflag: tar.TypeSymlink, Linkname: ".."},
+			{Name: "jdk/lib", Typeflag: tar.TypeSymlink, Linkname: "up/../x"},
+			{Name: "jdk/lib", Typeflag: tar.TypeReg},
+		}, true},
+		{"chained symlinks", []*tar.Header{
+			{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			{Name: "x", Typeflag: tar.TypeSymlink, Linkname: "a/.."},
+		}, true},
+		{"chained symlinks in reverse", []*tar.Header{
+			{Name: "x", Typeflag: tar.TypeSymlink, Linkname: "a/.."},
+			{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			for _, header := range tt.entries {
+				header.Mode = 0644
+				require.NoError(t, tw.WriteHeader(header))
+			}
+			require.NoError(t, tw.Close())
+			require.NoError(t, gz.Close())
+
+			root := t.TempDir()
+			dest := filepath.Join(root, "a", "b")
+			require.NoError(t, os.MkdirAll(dest, 0755))
+			err := ExtractTarGz(&buf, dest)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsafePath)
+				assert.NoFileExists(t, filepath.Join(root, "x"))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
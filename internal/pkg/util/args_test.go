@@ -0,0 +1,13 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitArgs(t *testing.T) {
+	require.Nil(t, SplitArgs("  "))
+	require.Equal(t, []string{"-Xss2M", "-Da=b c", "-Dd="}, SplitArgs(` -Xss2M  '-Da=b c' -Dd=""`))
+	require.Equal(t, []string{"-Xss2M", "-Da=b c", "-Dd="}, SplitArgs(JoinArgs([]string{"-Xss2M", "-Da=b c", "-Dd="})))
+}
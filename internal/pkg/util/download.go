@@ -2,6 +2,7 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"encoding/json"
 	"errors"
@@ -13,20 +14,24 @@ import (
 	"path"
 )
 
+// ErrOffline is returned when a resource is not present locally and offline mode
+// prevents fetching it.
+var ErrOffline = errors.New("not available offline")
+
 type FileDownload struct {
 	Sha1 string `json:"sha1"`
 	Size int64  `json:"size"`
 	Url  string `json:"url"`
 }
 
-func ReadOrDownload(id, file string, dl FileDownload, ptr interface{}) error {
+func ReadOrDownload(ctx context.Context, client *http.Client, file string, dl FileDownload, ptr interface{}) error {
 	if _, err := os.Stat(file); err == nil {
 		return ReadFile(file, ptr)
 	} else if errors.Is(err, fs.ErrNotExist) {
 		data := new(bytes.Buffer)
 
 		println("download", dl.Url) //todo remove me/add some callback for progress
-		if err := downloadFile(file, dl, data); err != nil {
+		if err := downloadFile(ctx, client, file, dl, data); err != nil {
 			return err
 		}
 
@@ -36,33 +41,64 @@ func ReadOrDownload(id, file string, dl FileDownload, ptr interface{}) error {
 	}
 }
 
-func Download(id, file string, dl FileDownload) error {
+func Download(ctx context.Context, client *http.Client, file string, dl FileDownload) error {
 	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
 		println("download", dl.Url) //todo remove me/add some callback for progress
-		return downloadFile(file, dl)
+		return downloadFile(ctx, client, file, dl)
 	}
 	return nil
 }
 
-func downloadFile(file string, download FileDownload, listeners ...io.Writer) error {
+// FetchJson decodes the json response from the given url into ptr without writing it to disk.
+func FetchJson(ctx context.Context, client *http.Client, url string, ptr interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", res.StatusCode, url)
+	}
+	return json.NewDecoder(res.Body).Decode(ptr)
+}
+
+// downloadFile writes the download to a temporary `.part` file next to the target, which is only renamed to
+// the target once the download is complete and validated. The temporary file is removed on any failure,
+// including cancellation of the context.
+func downloadFile(ctx context.Context, client *http.Client, file string, download FileDownload, listeners ...io.Writer) (err error) {
 	// Create parent directory
 	if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
 		return err
 	}
 
 	// Open request
-	res, err := http.Get(download.Url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, download.Url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 
-	// Create file
-	f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	// Create temporary file
+	partFile := file + ".part"
+	f, err := os.OpenFile(partFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
+	defer func() {
+		_ = f.Close()
+		if err != nil {
+			_ = os.Remove(partFile) // Attempt to delete the partial file
+		}
+	}()
 
 	// Copy data to file, hash, and listeners
 	hash := sha1.New()
@@ -71,7 +107,6 @@ func downloadFile(file string, download FileDownload, listeners ...io.Writer) er
 		writers = append(writers, hash)
 	}
 	if _, err = io.Copy(io.MultiWriter(writers...), res.Body); err != nil {
-		_ = os.Remove(file) // Attempt to delete the file
 		return err
 	}
 
@@ -79,14 +114,14 @@ func downloadFile(file string, download FileDownload, listeners ...io.Writer) er
 	if download.Sha1 != "" {
 		h := fmt.Sprintf("%x", hash.Sum(nil))
 		if h != download.Sha1 {
-			// Attempt to delete the file
-			_ = os.Remove(file)
-
 			return fmt.Errorf("FileDownload hash mismatch: %s != %s", h, download.Sha1)
 		}
 	}
 
-	return nil
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partFile, file)
 }
 
 func ReadFile(file string, ptr interface{}) error {
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	file := path.Join(t.TempDir(), "file")
+	dl := FileDownload{Url: server.URL, Sha1: "040f06fd774092478d450774f5ba30c5da78acc8"}
+	require.NoError(t, Download(context.Background(), http.DefaultClient, file, dl))
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+	assert.NoFileExists(t, file+".part")
+}
+
+func TestDownloadCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+
+		// Interrupt in the middle of the body
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	file := path.Join(t.TempDir(), "file")
+	err := Download(ctx, http.DefaultClient, file, FileDownload{Url: server.URL})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, file)
+	assert.NoFileExists(t, file+".part")
+}
+
+func TestDownloadHashMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	file := path.Join(t.TempDir(), "file")
+	err := Download(context.Background(), http.DefaultClient, file, FileDownload{Url: server.URL, Sha1: "invalid"})
+	assert.Error(t, err)
+	assert.NoFileExists(t, file)
+	assert.NoFileExists(t, file+".part")
+}
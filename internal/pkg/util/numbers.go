@@ -21,3 +21,16 @@ func FormatCount(num int) string {
 		return fmt.Sprintf("%d", num)
 	}
 }
+
+func FormatBytes(num int64) string {
+	const unit = 1024
+	if num < unit {
+		return fmt.Sprintf("%d B", num)
+	}
+	div, exp := int64(unit), 0
+	for n := num / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(num)/float64(div), "KMGTPE"[exp])
+}
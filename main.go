@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -38,7 +40,11 @@ func main() {
 	app := cli.NewApp(cli.BuildInfo{Version: version, Commit: commit, Date: date, Modified: modified, Source: source == "yes"})
 	rootCmd := mc.NewRootCmd(app)
 
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.Execute(); errors.Is(err, context.Canceled) {
+		// Interrupted, conventionally exit with 128 + SIGINT
+		_, _ = fmt.Fprintln(os.Stderr, "interrupted")
+		os.Exit(130)
+	} else if err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}